@@ -0,0 +1,949 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: api/subscribers/v1/subscribers.proto
+
+package subscribersv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Subscription struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceName  string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	MonthlyPrice int64                  `protobuf:"varint,3,opt,name=monthly_price,json=monthlyPrice,proto3" json:"monthly_price,omitempty"`
+	UserId       string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Формат 2006-01.
+	StartedAt string  `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	EndedAt   *string `protobuf:"bytes,6,opt,name=ended_at,json=endedAt,proto3,oneof" json:"ended_at,omitempty"`
+	// entertainment, software, utilities, education или other; пусто — без категории.
+	Category string   `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
+	Tags     []string `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Период оплаты в месяцах; 0 — ежемесячно.
+	BillingPeriod int32 `protobuf:"varint,9,opt,name=billing_period,json=billingPeriod,proto3" json:"billing_period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Subscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscription) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Subscription) GetMonthlyPrice() int64 {
+	if x != nil {
+		return x.MonthlyPrice
+	}
+	return 0
+}
+
+func (x *Subscription) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Subscription) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetEndedAt() string {
+	if x != nil && x.EndedAt != nil {
+		return *x.EndedAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Subscription) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Subscription) GetBillingPeriod() int32 {
+	if x != nil {
+		return x.BillingPeriod
+	}
+	return 0
+}
+
+type CreateSubscriptionRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price       int64                  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+	UserId      string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	StartDate   string                 `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate     *string                `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	// Без категории берётся категория из каталога.
+	Category string   `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	Tags     []string `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	// 1, 3, 6 или 12; 0 — ежемесячно.
+	BillingPeriod int32 `protobuf:"varint,8,opt,name=billing_period,json=billingPeriod,proto3" json:"billing_period,omitempty"`
+	// Тариф из каталога; при нулевой price берётся его цена.
+	Plan          string `protobuf:"bytes,9,opt,name=plan,proto3" json:"plan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequest) Reset() {
+	*x = CreateSubscriptionRequest{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateSubscriptionRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateSubscriptionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetEndDate() string {
+	if x != nil && x.EndDate != nil {
+		return *x.EndDate
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionRequest) GetBillingPeriod() int32 {
+	if x != nil {
+		return x.BillingPeriod
+	}
+	return 0
+}
+
+func (x *CreateSubscriptionRequest) GetPlan() string {
+	if x != nil {
+		return x.Plan
+	}
+	return ""
+}
+
+type CreateSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionResponse) Reset() {
+	*x = CreateSubscriptionResponse{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionResponse) ProtoMessage() {}
+
+func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateSubscriptionResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionRequest) Reset() {
+	*x = GetSubscriptionRequest{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSubscriptionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type UpdateSubscriptionRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceName *string                `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Price       *int64                 `protobuf:"varint,3,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	StartDate   *string                `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3,oneof" json:"start_date,omitempty"`
+	// Пустая строка удаляет дату окончания.
+	EndDate *string `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	// Пустая строка удаляет категорию.
+	Category *string `protobuf:"bytes,6,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	// Заменяет теги; пустой список удаляет все.
+	Tags          *Tags  `protobuf:"bytes,7,opt,name=tags,proto3,oneof" json:"tags,omitempty"`
+	BillingPeriod *int32 `protobuf:"varint,8,opt,name=billing_period,json=billingPeriod,proto3,oneof" json:"billing_period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionRequest) Reset() {
+	*x = UpdateSubscriptionRequest{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionRequest) ProtoMessage() {}
+
+func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetStartDate() string {
+	if x != nil && x.StartDate != nil {
+		return *x.StartDate
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetEndDate() string {
+	if x != nil && x.EndDate != nil {
+		return *x.EndDate
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetTags() *Tags {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionRequest) GetBillingPeriod() int32 {
+	if x != nil && x.BillingPeriod != nil {
+		return *x.BillingPeriod
+	}
+	return 0
+}
+
+type Tags struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tags) Reset() {
+	*x = Tags{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tags) ProtoMessage() {}
+
+func (x *Tags) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tags.ProtoReflect.Descriptor instead.
+func (*Tags) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Tags) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type UpdateSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionResponse) Reset() {
+	*x = UpdateSubscriptionResponse{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionResponse) ProtoMessage() {}
+
+func (x *UpdateSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{8}
+}
+
+type DeleteSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionRequest) Reset() {
+	*x = DeleteSubscriptionRequest{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionResponse) Reset() {
+	*x = DeleteSubscriptionResponse{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionResponse) ProtoMessage() {}
+
+func (x *DeleteSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{10}
+}
+
+type GetSubscriptionsTotalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	StartDate     string                 `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionsTotalRequest) Reset() {
+	*x = GetSubscriptionsTotalRequest{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionsTotalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionsTotalRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionsTotalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionsTotalRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionsTotalRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetSubscriptionsTotalRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsTotalRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsTotalRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsTotalRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+type GetSubscriptionsTotalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalPrice    int64                  `protobuf:"varint,1,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionsTotalResponse) Reset() {
+	*x = GetSubscriptionsTotalResponse{}
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionsTotalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionsTotalResponse) ProtoMessage() {}
+
+func (x *GetSubscriptionsTotalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscribers_v1_subscribers_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionsTotalResponse.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionsTotalResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscribers_v1_subscribers_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSubscriptionsTotalResponse) GetTotalPrice() int64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+var File_api_subscribers_v1_subscribers_proto protoreflect.FileDescriptor
+
+const file_api_subscribers_v1_subscribers_proto_rawDesc = "" +
+	"\n" +
+	"$api/subscribers/v1/subscribers.proto\x12\x0esubscribers.v1\"\xa2\x02\n" +
+	"\fSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12#\n" +
+	"\rmonthly_price\x18\x03 \x01(\x03R\fmonthlyPrice\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\tR\tstartedAt\x12\x1e\n" +
+	"\bended_at\x18\x06 \x01(\tH\x00R\aendedAt\x88\x01\x01\x12\x1a\n" +
+	"\bcategory\x18\a \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12%\n" +
+	"\x0ebilling_period\x18\t \x01(\x05R\rbillingPeriodB\v\n" +
+	"\t_ended_at\"\xa4\x02\n" +
+	"\x19CreateSubscriptionRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x03R\x05price\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tR\tstartDate\x12\x1e\n" +
+	"\bend_date\x18\x05 \x01(\tH\x00R\aendDate\x88\x01\x01\x12\x1a\n" +
+	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12%\n" +
+	"\x0ebilling_period\x18\b \x01(\x05R\rbillingPeriod\x12\x12\n" +
+	"\x04plan\x18\t \x01(\tR\x04planB\v\n" +
+	"\t_end_date\",\n" +
+	"\x1aCreateSubscriptionResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"(\n" +
+	"\x16GetSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"3\n" +
+	"\x18ListSubscriptionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"_\n" +
+	"\x19ListSubscriptionsResponse\x12B\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1c.subscribers.v1.SubscriptionR\rsubscriptions\"\x8e\x03\n" +
+	"\x19UpdateSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\fservice_name\x18\x02 \x01(\tH\x00R\vserviceName\x88\x01\x01\x12\x19\n" +
+	"\x05price\x18\x03 \x01(\x03H\x01R\x05price\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tH\x02R\tstartDate\x88\x01\x01\x12\x1e\n" +
+	"\bend_date\x18\x05 \x01(\tH\x03R\aendDate\x88\x01\x01\x12\x1f\n" +
+	"\bcategory\x18\x06 \x01(\tH\x04R\bcategory\x88\x01\x01\x12-\n" +
+	"\x04tags\x18\a \x01(\v2\x14.subscribers.v1.TagsH\x05R\x04tags\x88\x01\x01\x12*\n" +
+	"\x0ebilling_period\x18\b \x01(\x05H\x06R\rbillingPeriod\x88\x01\x01B\x0f\n" +
+	"\r_service_nameB\b\n" +
+	"\x06_priceB\r\n" +
+	"\v_start_dateB\v\n" +
+	"\t_end_dateB\v\n" +
+	"\t_categoryB\a\n" +
+	"\x05_tagsB\x11\n" +
+	"\x0f_billing_period\"\x1e\n" +
+	"\x04Tags\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"\x1c\n" +
+	"\x1aUpdateSubscriptionResponse\"+\n" +
+	"\x19DeleteSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1c\n" +
+	"\x1aDeleteSubscriptionResponse\"\x94\x01\n" +
+	"\x1cGetSubscriptionsTotalRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x03 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x04 \x01(\tR\aendDate\"@\n" +
+	"\x1dGetSubscriptionsTotalResponse\x12\x1f\n" +
+	"\vtotal_price\x18\x01 \x01(\x03R\n" +
+	"totalPrice2\x95\x05\n" +
+	"\x13SubscriptionService\x12k\n" +
+	"\x12CreateSubscription\x12).subscribers.v1.CreateSubscriptionRequest\x1a*.subscribers.v1.CreateSubscriptionResponse\x12W\n" +
+	"\x0fGetSubscription\x12&.subscribers.v1.GetSubscriptionRequest\x1a\x1c.subscribers.v1.Subscription\x12h\n" +
+	"\x11ListSubscriptions\x12(.subscribers.v1.ListSubscriptionsRequest\x1a).subscribers.v1.ListSubscriptionsResponse\x12k\n" +
+	"\x12UpdateSubscription\x12).subscribers.v1.UpdateSubscriptionRequest\x1a*.subscribers.v1.UpdateSubscriptionResponse\x12k\n" +
+	"\x12DeleteSubscription\x12).subscribers.v1.DeleteSubscriptionRequest\x1a*.subscribers.v1.DeleteSubscriptionResponse\x12t\n" +
+	"\x15GetSubscriptionsTotal\x12,.subscribers.v1.GetSubscriptionsTotalRequest\x1a-.subscribers.v1.GetSubscriptionsTotalResponseB.Z,subscribers/api/subscribers/v1;subscribersv1b\x06proto3"
+
+var (
+	file_api_subscribers_v1_subscribers_proto_rawDescOnce sync.Once
+	file_api_subscribers_v1_subscribers_proto_rawDescData []byte
+)
+
+func file_api_subscribers_v1_subscribers_proto_rawDescGZIP() []byte {
+	file_api_subscribers_v1_subscribers_proto_rawDescOnce.Do(func() {
+		file_api_subscribers_v1_subscribers_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_subscribers_v1_subscribers_proto_rawDesc), len(file_api_subscribers_v1_subscribers_proto_rawDesc)))
+	})
+	return file_api_subscribers_v1_subscribers_proto_rawDescData
+}
+
+var file_api_subscribers_v1_subscribers_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_api_subscribers_v1_subscribers_proto_goTypes = []any{
+	(*Subscription)(nil),                  // 0: subscribers.v1.Subscription
+	(*CreateSubscriptionRequest)(nil),     // 1: subscribers.v1.CreateSubscriptionRequest
+	(*CreateSubscriptionResponse)(nil),    // 2: subscribers.v1.CreateSubscriptionResponse
+	(*GetSubscriptionRequest)(nil),        // 3: subscribers.v1.GetSubscriptionRequest
+	(*ListSubscriptionsRequest)(nil),      // 4: subscribers.v1.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),     // 5: subscribers.v1.ListSubscriptionsResponse
+	(*UpdateSubscriptionRequest)(nil),     // 6: subscribers.v1.UpdateSubscriptionRequest
+	(*Tags)(nil),                          // 7: subscribers.v1.Tags
+	(*UpdateSubscriptionResponse)(nil),    // 8: subscribers.v1.UpdateSubscriptionResponse
+	(*DeleteSubscriptionRequest)(nil),     // 9: subscribers.v1.DeleteSubscriptionRequest
+	(*DeleteSubscriptionResponse)(nil),    // 10: subscribers.v1.DeleteSubscriptionResponse
+	(*GetSubscriptionsTotalRequest)(nil),  // 11: subscribers.v1.GetSubscriptionsTotalRequest
+	(*GetSubscriptionsTotalResponse)(nil), // 12: subscribers.v1.GetSubscriptionsTotalResponse
+}
+var file_api_subscribers_v1_subscribers_proto_depIdxs = []int32{
+	0,  // 0: subscribers.v1.ListSubscriptionsResponse.subscriptions:type_name -> subscribers.v1.Subscription
+	7,  // 1: subscribers.v1.UpdateSubscriptionRequest.tags:type_name -> subscribers.v1.Tags
+	1,  // 2: subscribers.v1.SubscriptionService.CreateSubscription:input_type -> subscribers.v1.CreateSubscriptionRequest
+	3,  // 3: subscribers.v1.SubscriptionService.GetSubscription:input_type -> subscribers.v1.GetSubscriptionRequest
+	4,  // 4: subscribers.v1.SubscriptionService.ListSubscriptions:input_type -> subscribers.v1.ListSubscriptionsRequest
+	6,  // 5: subscribers.v1.SubscriptionService.UpdateSubscription:input_type -> subscribers.v1.UpdateSubscriptionRequest
+	9,  // 6: subscribers.v1.SubscriptionService.DeleteSubscription:input_type -> subscribers.v1.DeleteSubscriptionRequest
+	11, // 7: subscribers.v1.SubscriptionService.GetSubscriptionsTotal:input_type -> subscribers.v1.GetSubscriptionsTotalRequest
+	2,  // 8: subscribers.v1.SubscriptionService.CreateSubscription:output_type -> subscribers.v1.CreateSubscriptionResponse
+	0,  // 9: subscribers.v1.SubscriptionService.GetSubscription:output_type -> subscribers.v1.Subscription
+	5,  // 10: subscribers.v1.SubscriptionService.ListSubscriptions:output_type -> subscribers.v1.ListSubscriptionsResponse
+	8,  // 11: subscribers.v1.SubscriptionService.UpdateSubscription:output_type -> subscribers.v1.UpdateSubscriptionResponse
+	10, // 12: subscribers.v1.SubscriptionService.DeleteSubscription:output_type -> subscribers.v1.DeleteSubscriptionResponse
+	12, // 13: subscribers.v1.SubscriptionService.GetSubscriptionsTotal:output_type -> subscribers.v1.GetSubscriptionsTotalResponse
+	8,  // [8:14] is the sub-list for method output_type
+	2,  // [2:8] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_subscribers_v1_subscribers_proto_init() }
+func file_api_subscribers_v1_subscribers_proto_init() {
+	if File_api_subscribers_v1_subscribers_proto != nil {
+		return
+	}
+	file_api_subscribers_v1_subscribers_proto_msgTypes[0].OneofWrappers = []any{}
+	file_api_subscribers_v1_subscribers_proto_msgTypes[1].OneofWrappers = []any{}
+	file_api_subscribers_v1_subscribers_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_subscribers_v1_subscribers_proto_rawDesc), len(file_api_subscribers_v1_subscribers_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_subscribers_v1_subscribers_proto_goTypes,
+		DependencyIndexes: file_api_subscribers_v1_subscribers_proto_depIdxs,
+		MessageInfos:      file_api_subscribers_v1_subscribers_proto_msgTypes,
+	}.Build()
+	File_api_subscribers_v1_subscribers_proto = out.File
+	file_api_subscribers_v1_subscribers_proto_goTypes = nil
+	file_api_subscribers_v1_subscribers_proto_depIdxs = nil
+}
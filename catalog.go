@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"subscribers/config"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func catalogCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Управление каталогом сервисов",
+	}
+
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Загрузить каталог из YAML файла",
+		Long: `Файл содержит список сервисов:
+
+  - name: Netflix
+    category: entertainment
+    website: https://www.netflix.com
+    aliases: [netflix premium, нетфликс]
+    plans:
+      - {name: Standard, price: 799}
+
+Сервисы, название которых уже есть в каталоге (с учётом алиасов), заменяются.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var entries []models.CatalogServiceRequest
+			if err := yaml.Unmarshal(data, &entries); err != nil {
+				return fmt.Errorf("parse %s: %w", args[0], err)
+			}
+
+			created, updated, err := services.ImportCatalog(connectDB(cmd.Context(), cfg), entries)
+			if err != nil {
+				return fmt.Errorf("failed to import catalog: %w", err)
+			}
+			fmt.Printf("catalog imported: %d created, %d updated\n", created, updated)
+			return nil
+		},
+	}
+
+	var apply bool
+	mapCmd := &cobra.Command{
+		Use:   "map",
+		Short: "Сопоставить названия подписок с каталогом",
+		Long: `Показывает, к какому сервису каталога сводится каждое название подписки.
+С --apply подписки переименовываются в каноническое название и связываются
+с каталогом; события и webhook'и при этом не создаются.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mappings, err := services.MapServiceNames(connectDB(cmd.Context(), cfg), apply)
+			if err != nil {
+				return fmt.Errorf("failed to map service names: %w", err)
+			}
+
+			unmatched := 0
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tCATALOG\tSUBSCRIPTIONS")
+			for _, m := range mappings {
+				canonical := m.CanonicalName
+				if m.ServiceID == nil {
+					canonical = "-"
+					unmatched++
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d\n", m.Name, canonical, m.Subscriptions)
+			}
+			w.Flush()
+
+			if !apply {
+				fmt.Println("dry run, pass --apply to update subscriptions")
+			}
+			if unmatched > 0 {
+				fmt.Printf("%d names are not in the catalog, add them as services or aliases\n", unmatched)
+			}
+			return nil
+		},
+	}
+	mapCmd.Flags().BoolVar(&apply, "apply", false, "переименовать подписки")
+
+	cmd.AddCommand(importCmd, mapCmd)
+	return cmd
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cliConfig — содержимое config.yaml:
+//
+//	server: http://localhost:8080
+//	api_key: secret
+//	user_id: 123e4567-e89b-12d3-a456-426614174001
+//	output: table
+type cliConfig struct {
+	Server string `yaml:"server"`
+	APIKey string `yaml:"api_key"`
+	UserID string `yaml:"user_id"`
+	Output string `yaml:"output"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "subscribers", "config.yaml")
+}
+
+func loadCLIConfig(path string) (cliConfig, error) {
+	cfg := cliConfig{Server: "http://localhost:8080"}
+
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv("SUBSCRIBERS_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		path = defaultConfigPath()
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("parse config %s: %w", path, err)
+			}
+		case errors.Is(err, os.ErrNotExist) && !explicit:
+		default:
+			return cfg, fmt.Errorf("read config: %w", err)
+		}
+	}
+
+	if v := os.Getenv("SUBSCRIBERS_SERVER"); v != "" {
+		cfg.Server = v
+	}
+	if v := os.Getenv("SUBSCRIBERS_API_KEY"); v != "" {
+		cfg.APIKey = v
+	}
+	if v := os.Getenv("SUBSCRIBERS_USER_ID"); v != "" {
+		cfg.UserID = v
+	}
+	return cfg, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Форматы, которые принимает CLI. Сервер понимает только 01-2006 и 2006-01,
+// поэтому дата всегда нормализуется перед отправкой.
+var dateLayouts = []string{
+	"01-2006",
+	"2006-01",
+	"1-2006",
+	"01.2006",
+	"01/2006",
+	"2006/01",
+	"2006-01-02",
+	"02.01.2006",
+	"Jan 2006",
+	"January 2006",
+}
+
+func normalizeDate(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("01-2006"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date %q: expected MM-YYYY, e.g. 01-2024", s)
+}
@@ -0,0 +1,84 @@
+// Команда subscribers — консольный клиент HTTP API сервиса подписок.
+package main
+
+import (
+	"fmt"
+	"os"
+	"subscribers/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+type app struct {
+	configPath string
+	server     string
+	output     string
+
+	cfg    cliConfig
+	client *client.Client
+}
+
+func main() {
+	a := &app{}
+
+	root := &cobra.Command{
+		Use:           "subscribers",
+		Short:         "Управление подписками через HTTP API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return a.init()
+		},
+	}
+
+	root.PersistentFlags().StringVar(&a.configPath, "config", "", "путь к файлу конфигурации (по умолчанию ~/.config/subscribers/config.yaml)")
+	root.PersistentFlags().StringVar(&a.server, "server", "", "адрес API, например http://localhost:8080")
+	root.PersistentFlags().StringVarP(&a.output, "output", "o", "", "формат вывода: table, json, yaml")
+
+	root.AddCommand(
+		a.addCommand(),
+		a.listCommand(),
+		a.showCommand(),
+		a.editCommand(),
+		a.rmCommand(),
+		a.totalCommand(),
+		a.importCommand(),
+		a.exportCommand(),
+	)
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// init читает конфигурацию и создаёт клиента. Флаги командной строки
+// имеют приоритет над переменными окружения, а те — над файлом.
+func (a *app) init() error {
+	cfg, err := loadCLIConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+
+	if a.server != "" {
+		cfg.Server = a.server
+	}
+	if a.output != "" {
+		cfg.Output = a.output
+	}
+	if cfg.Output == "" {
+		cfg.Output = outputTable
+	}
+	if cfg.Output != outputTable && cfg.Output != outputJSON && cfg.Output != outputYAML {
+		return fmt.Errorf("unknown output format %q", cfg.Output)
+	}
+
+	var opts []client.Option
+	if cfg.APIKey != "" {
+		opts = append(opts, client.WithHeader("X-API-Key", cfg.APIKey))
+	}
+
+	a.cfg = cfg
+	a.client = client.New(cfg.Server, opts...)
+	return nil
+}
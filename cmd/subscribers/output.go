@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"subscribers/pkg/client"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// subscriptionView — представление подписки для вывода и экспорта:
+// даты в том же формате MM-YYYY, в котором их принимает CLI.
+type subscriptionView struct {
+	ID          string `json:"id,omitempty" yaml:"id,omitempty"`
+	ServiceName string `json:"service_name" yaml:"service_name"`
+	Price       int    `json:"price" yaml:"price"`
+	UserID      string `json:"user_id" yaml:"user_id"`
+	StartDate   string `json:"start_date" yaml:"start_date"`
+	EndDate     string `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+}
+
+func toView(sub client.Subscription) subscriptionView {
+	view := subscriptionView{
+		ID:          sub.ID.String(),
+		ServiceName: sub.ServiceName,
+		Price:       sub.MonthlyPrice,
+		UserID:      sub.UserID.String(),
+		StartDate:   formatMonth(sub.StartedAt),
+	}
+	if sub.EndedAt != nil {
+		view.EndDate = formatMonth(*sub.EndedAt)
+	}
+	return view
+}
+
+func formatMonth(ym client.YearMonth) string {
+	return fmt.Sprintf("%02d-%04d", ym.Month, ym.Year)
+}
+
+func (a *app) printSubscriptions(subs []client.Subscription) error {
+	views := make([]subscriptionView, 0, len(subs))
+	for _, sub := range subs {
+		views = append(views, toView(sub))
+	}
+
+	if a.cfg.Output != outputTable {
+		return writeStructured(os.Stdout, a.cfg.Output, views)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSERVICE\tPRICE\tSTART\tEND")
+	for _, v := range views {
+		end := v.EndDate
+		if end == "" {
+			end = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", v.ID, v.ServiceName, v.Price, v.StartDate, end)
+	}
+	return w.Flush()
+}
+
+func (a *app) printSubscription(sub client.Subscription) error {
+	view := toView(sub)
+	if a.cfg.Output != outputTable {
+		return writeStructured(os.Stdout, a.cfg.Output, view)
+	}
+
+	end := view.EndDate
+	if end == "" {
+		end = "-"
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", view.ID)
+	fmt.Fprintf(w, "Service:\t%s\n", view.ServiceName)
+	fmt.Fprintf(w, "Price:\t%d\n", view.Price)
+	fmt.Fprintf(w, "User:\t%s\n", view.UserID)
+	fmt.Fprintf(w, "Start:\t%s\n", view.StartDate)
+	fmt.Fprintf(w, "End:\t%s\n", end)
+	return w.Flush()
+}
+
+// printResult выводит произвольный результат: в table-режиме — строкой text.
+func (a *app) printResult(text string, value interface{}) error {
+	if a.cfg.Output == outputTable {
+		_, err := fmt.Fprintln(os.Stdout, text)
+		return err
+	}
+	return writeStructured(os.Stdout, a.cfg.Output, value)
+}
+
+func writeStructured(w io.Writer, format string, value interface{}) error {
+	switch format {
+	case outputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		defer enc.Close()
+		return enc.Encode(value)
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+}
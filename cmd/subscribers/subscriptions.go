@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"subscribers/pkg/client"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func (a *app) userID(flag string) (uuid.UUID, error) {
+	value := flag
+	if value == "" {
+		value = a.cfg.UserID
+	}
+	if value == "" {
+		return uuid.Nil, errors.New("user is required: pass --user or set user_id in the config")
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid user ID %q", value)
+	}
+	return id, nil
+}
+
+func (a *app) addCommand() *cobra.Command {
+	var user, service, start, end string
+	var price int
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Создать подписку",
+		Example: `  subscribers add --service Netflix --price 799 --start 01-2024
+  subscribers add --service Spotify --price 299 --start 2024-03 --end 12-2024`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			userID, err := a.userID(user)
+			if err != nil {
+				return err
+			}
+			startDate, err := normalizeDate(start)
+			if err != nil {
+				return err
+			}
+
+			req := client.CreateSubscriptionRequest{
+				ServiceName: service,
+				Price:       price,
+				UserID:      userID.String(),
+				StartDate:   startDate,
+			}
+			if end != "" {
+				endDate, err := normalizeDate(end)
+				if err != nil {
+					return err
+				}
+				req.EndDate = &endDate
+			}
+
+			id, err := a.client.CreateSubscription(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			return a.printResult(id.String(), map[string]string{"id": id.String()})
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "ID пользователя (по умолчанию user_id из конфигурации)")
+	cmd.Flags().StringVar(&service, "service", "", "название сервиса")
+	cmd.Flags().IntVar(&price, "price", 0, "цена в месяц")
+	cmd.Flags().StringVar(&start, "start", "", "месяц начала (MM-YYYY)")
+	cmd.Flags().StringVar(&end, "end", "", "месяц окончания (MM-YYYY)")
+	cmd.MarkFlagRequired("service")
+	cmd.MarkFlagRequired("price")
+	cmd.MarkFlagRequired("start")
+	return cmd
+}
+
+func (a *app) listCommand() *cobra.Command {
+	var user string
+	var limit, offset int
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Показать подписки пользователя",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			userID, err := a.userID(user)
+			if err != nil {
+				return err
+			}
+
+			subs, err := a.client.ListSubscriptions(cmd.Context(), userID, client.ListOptions{Limit: limit, Offset: offset})
+			if err != nil {
+				return err
+			}
+			return a.printSubscriptions(subs)
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "ID пользователя (по умолчанию user_id из конфигурации)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "размер страницы")
+	cmd.Flags().IntVar(&offset, "offset", 0, "смещение")
+	return cmd
+}
+
+func (a *app) showCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Показать подписку",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid subscription ID %q", args[0])
+			}
+
+			sub, err := a.client.GetSubscription(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+			return a.printSubscription(*sub)
+		},
+	}
+}
+
+func (a *app) editCommand() *cobra.Command {
+	var service, start, end string
+	var price int
+	var clearEnd bool
+
+	cmd := &cobra.Command{
+		Use:     "edit <id>",
+		Short:   "Изменить подписку",
+		Example: `  subscribers edit 3f0c... --price 999 --end 06-2025`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid subscription ID %q", args[0])
+			}
+
+			var req client.UpdateSubscriptionRequest
+			if cmd.Flags().Changed("service") {
+				req.ServiceName = &service
+			}
+			if cmd.Flags().Changed("price") {
+				req.Price = &price
+			}
+			if start != "" {
+				startDate, err := normalizeDate(start)
+				if err != nil {
+					return err
+				}
+				req.StartDate = &startDate
+			}
+			switch {
+			case clearEnd:
+				empty := ""
+				req.EndDate = &empty
+			case end != "":
+				endDate, err := normalizeDate(end)
+				if err != nil {
+					return err
+				}
+				req.EndDate = &endDate
+			}
+
+			if err := a.client.UpdateSubscription(cmd.Context(), id, req); err != nil {
+				return err
+			}
+			return a.printResult("updated", map[string]string{"id": id.String(), "status": "updated"})
+		},
+	}
+
+	cmd.Flags().StringVar(&service, "service", "", "название сервиса")
+	cmd.Flags().IntVar(&price, "price", 0, "цена в месяц")
+	cmd.Flags().StringVar(&start, "start", "", "месяц начала (MM-YYYY)")
+	cmd.Flags().StringVar(&end, "end", "", "месяц окончания (MM-YYYY)")
+	cmd.Flags().BoolVar(&clearEnd, "clear-end", false, "убрать дату окончания")
+	cmd.MarkFlagsMutuallyExclusive("end", "clear-end")
+	return cmd
+}
+
+func (a *app) rmCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm <id>...",
+		Aliases: []string{"delete"},
+		Short:   "Удалить подписки",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, arg := range args {
+				id, err := uuid.Parse(arg)
+				if err != nil {
+					return fmt.Errorf("invalid subscription ID %q", arg)
+				}
+				if err := a.client.DeleteSubscription(cmd.Context(), id); err != nil {
+					return fmt.Errorf("delete %s: %w", id, err)
+				}
+			}
+			return a.printResult("deleted", map[string]interface{}{"deleted": args})
+		},
+	}
+}
+
+func (a *app) totalCommand() *cobra.Command {
+	var user, service, from, to string
+
+	cmd := &cobra.Command{
+		Use:   "total",
+		Short: "Суммарная стоимость подписок за период",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			userID, err := a.userID(user)
+			if err != nil {
+				return err
+			}
+
+			opts := client.TotalOptions{ServiceName: service}
+			if from != "" {
+				if opts.StartDate, err = normalizeDate(from); err != nil {
+					return err
+				}
+			}
+			if to != "" {
+				if opts.EndDate, err = normalizeDate(to); err != nil {
+					return err
+				}
+			}
+
+			total, err := a.client.GetSubscriptionsTotal(cmd.Context(), userID, opts)
+			if err != nil {
+				return err
+			}
+			return a.printResult(fmt.Sprint(total), map[string]int{"total_price": total})
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "ID пользователя (по умолчанию user_id из конфигурации)")
+	cmd.Flags().StringVar(&service, "service", "", "фильтр по сервису")
+	cmd.Flags().StringVar(&from, "from", "", "начало периода (MM-YYYY)")
+	cmd.Flags().StringVar(&to, "to", "", "конец периода (MM-YYYY)")
+	return cmd
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"subscribers/pkg/client"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var csvHeader = []string{"id", "service_name", "price", "user_id", "start_date", "end_date"}
+
+func fileFormat(flag, path string) (string, error) {
+	format := flag
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch format {
+	case "json", "csv":
+		return format, nil
+	case "yaml", "yml":
+		return "yaml", nil
+	case "":
+		return "json", nil
+	default:
+		return "", fmt.Errorf("unknown file format %q: use json, yaml or csv", format)
+	}
+}
+
+func (a *app) exportCommand() *cobra.Command {
+	var user, file, format string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Выгрузить подписки пользователя в JSON, YAML или CSV",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			userID, err := a.userID(user)
+			if err != nil {
+				return err
+			}
+			format, err := fileFormat(format, file)
+			if err != nil {
+				return err
+			}
+
+			var views []subscriptionView
+			for sub, err := range a.client.AllSubscriptions(cmd.Context(), userID, 100) {
+				if err != nil {
+					return err
+				}
+				views = append(views, toView(sub))
+			}
+
+			var w io.Writer = os.Stdout
+			if file != "" && file != "-" {
+				f, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if format == "csv" {
+				return writeCSV(w, views)
+			}
+			return writeStructured(w, format, views)
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "ID пользователя (по умолчанию user_id из конфигурации)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "файл для выгрузки (по умолчанию stdout)")
+	cmd.Flags().StringVar(&format, "format", "", "json, yaml или csv (по умолчанию — по расширению файла)")
+	return cmd
+}
+
+func (a *app) importCommand() *cobra.Command {
+	var user, format string
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Загрузить подписки из JSON, YAML или CSV",
+		Long: `Загружает подписки из файла, созданного командой export, или подготовленного вручную.
+Поле id игнорируется. Если user_id в записи не указан, используется --user.
+Уже существующие подписки пропускаются.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := fileFormat(format, args[0])
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			views, err := parseImport(format, data)
+			if err != nil {
+				return err
+			}
+
+			created, skipped := 0, 0
+			for i, view := range views {
+				if view.UserID == "" {
+					userID, err := a.userID(user)
+					if err != nil {
+						return fmt.Errorf("record %d: %w", i+1, err)
+					}
+					view.UserID = userID.String()
+				}
+				req, err := view.createRequest()
+				if err != nil {
+					return fmt.Errorf("record %d: %w", i+1, err)
+				}
+
+				_, err = a.client.CreateSubscription(cmd.Context(), req)
+				if errors.Is(err, client.ErrConflict) {
+					skipped++
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("record %d (%s): %w", i+1, view.ServiceName, err)
+				}
+				created++
+			}
+
+			return a.printResult(
+				fmt.Sprintf("created: %d, skipped (already exist): %d", created, skipped),
+				map[string]int{"created": created, "skipped": skipped},
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "ID пользователя для записей без user_id")
+	cmd.Flags().StringVar(&format, "format", "", "json, yaml или csv (по умолчанию — по расширению файла)")
+	return cmd
+}
+
+func (v subscriptionView) createRequest() (client.CreateSubscriptionRequest, error) {
+	startDate, err := normalizeDate(v.StartDate)
+	if err != nil {
+		return client.CreateSubscriptionRequest{}, err
+	}
+	req := client.CreateSubscriptionRequest{
+		ServiceName: v.ServiceName,
+		Price:       v.Price,
+		UserID:      v.UserID,
+		StartDate:   startDate,
+	}
+	if v.EndDate != "" {
+		endDate, err := normalizeDate(v.EndDate)
+		if err != nil {
+			return req, err
+		}
+		req.EndDate = &endDate
+	}
+	return req, nil
+}
+
+func parseImport(format string, data []byte) ([]subscriptionView, error) {
+	var views []subscriptionView
+	switch format {
+	case "csv":
+		return readCSV(strings.NewReader(string(data)))
+	case "yaml":
+		if err := yaml.Unmarshal(data, &views); err != nil {
+			return nil, fmt.Errorf("parse yaml: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(data, &views); err != nil {
+			return nil, fmt.Errorf("parse json: %w", err)
+		}
+	}
+	return views, nil
+}
+
+func writeCSV(w io.Writer, views []subscriptionView) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, v := range views {
+		record := []string{v.ID, v.ServiceName, strconv.Itoa(v.Price), v.UserID, v.StartDate, v.EndDate}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readCSV сопоставляет колонки по заголовку, поэтому порядок колонок и лишние колонки не важны.
+func readCSV(r io.Reader) ([]subscriptionView, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	get := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	views := make([]subscriptionView, 0, len(records)-1)
+	for line, record := range records[1:] {
+		price, err := strconv.Atoi(get(record, "price"))
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: invalid price", line+2)
+		}
+		views = append(views, subscriptionView{
+			ServiceName: get(record, "service_name"),
+			Price:       price,
+			UserID:      get(record, "user_id"),
+			StartDate:   get(record, "start_date"),
+			EndDate:     get(record, "end_date"),
+		})
+	}
+	return views, nil
+}
@@ -1,14 +1,25 @@
 package config
 
 import (
-	"log"
-	"os"
-
-	"github.com/joho/godotenv"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"subscribers/internal/certs"
+	"subscribers/internal/ratelimit"
+	"time"
 )
 
 type Config struct {
-	AppPort    string
+	AppPort  string
+	GRPCPort string
+	// HealthPort — порт отдельного HTTP сервера без TLS только с /healthz и
+	// /readyz для проверок контейнера; пусто — не запускается.
+	HealthPort string
 	DBHost     string
 	DBPort     string
 	DBUser     string
@@ -16,28 +27,260 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 	LogLevel   string
+
+	LogFormat     string
+	LogFile       string
+	LogMaxSizeMB  int
+	LogMaxBackups int
+	LogMaxAgeDays int
+
+	// DBReplicaHosts — реплики для чтения в формате host или host:port.
+	DBReplicaHosts    []string
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+	DBConnectTimeout  time.Duration
+
+	HTTPReadTimeout       time.Duration
+	HTTPReadHeaderTimeout time.Duration
+	HTTPWriteTimeout      time.Duration
+	HTTPIdleTimeout       time.Duration
+	ShutdownTimeout       time.Duration
+	// ShutdownDelay — сколько после сигнала остановки /readyz отвечает 503,
+	// а серверы ещё принимают запросы.
+	ShutdownDelay      time.Duration
+	HealthCheckTimeout time.Duration
+	// MaxBodyBytes — лимит размера тела запроса, 0 — без лимита.
+	MaxBodyBytes int
+	HSTSMaxAge   time.Duration
+	// TrustedProxies — IP и подсети прокси, чьим X-Forwarded-For доверяется.
+	TrustedProxies []string
+
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	WebhookMaxAttempts  int
+	WebhookTimeout      time.Duration
+	WebhookPollInterval time.Duration
+
+	BrokerType         string
+	BrokerURL          string
+	BrokerTopic        string
+	OutboxPollInterval time.Duration
+	// OutboxRetention — сколько хранятся события outbox. Без брокера удаляются
+	// и неопубликованные: публиковать их некому.
+	OutboxRetention time.Duration
+
+	// PriceChangeInterval — как часто применяются наступившие изменения цены.
+	PriceChangeInterval time.Duration
+	// InsightsInterval — как часто подписки анализируются на повышения цены,
+	// дубликаты, забытые подписки и скачки расходов.
+	InsightsInterval time.Duration
+
+	GraphQLMaxComplexity int
+	GraphQLMaxDepth      int
+
+	AdminToken string
+	// AdminIdentities — сервисы из TLS_CLIENT_IDENTITIES, которым /admin доступен без токена.
+	AdminIdentities []string
+	// UserIdentities — сервисы из TLS_CLIENT_IDENTITIES, которые сами проверяют
+	// пользователя и передают его в X-User-ID (x-user-id в gRPC).
+	UserIdentities []string
+
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSClientCAFile     string
+	TLSClientAuth       string
+	TLSClientIdentities []string
+	TLSReloadInterval   time.Duration
+
+	// RateLimitBackend — memory или redis; лимиты задаются как "100/1m", "off" отключает.
+	RateLimitBackend  string
+	RateLimitRedisURL string
+	RateLimitAPI      string
+	RateLimitTotal    string
+	RateLimitGraphQL  string
+
+	ServiceName      string
+	OTLPEndpoint     string
+	TraceSampleRatio float64
+
+	// sources — откуда взято каждое значение, для print-config.
+	sources     map[string]string
+	loadOptions LoadOptions
+}
+
+func (c *Config) DSN() string {
+	return c.dsn(net.JoinHostPort(c.DBHost, c.DBPort))
 }
 
-func LoadConfig() *Config {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println(".env отсутсвует")
+// ReplicaDSNs возвращает DSN реплик; учётные данные и sslmode общие с primary.
+func (c *Config) ReplicaDSNs() []string {
+	dsns := make([]string, 0, len(c.DBReplicaHosts))
+	for _, host := range c.DBReplicaHosts {
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, c.DBPort)
+		}
+		dsns = append(dsns, c.dsn(host))
 	}
+	return dsns
+}
 
-	return &Config{
-		AppPort:    getEnv("APP_PORT", "8080"),
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "postgres"),
-		DBName:     getEnv("DB_NAME", "subscription_db"),
-		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
-		LogLevel:   getEnv("LOG_LEVEL", "info"),
+func (c *Config) dsn(hostPort string) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.DBUser, c.DBPassword),
+		Host:     hostPort,
+		Path:     "/" + c.DBName,
+		RawQuery: url.Values{"sslmode": {c.DBSSLMode}}.Encode(),
 	}
+	return u.String()
 }
 
-func getEnv(key, fallback string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// Validate проверяет конфигурацию и возвращает все найденные ошибки сразу.
+func (c *Config) Validate() error {
+	var errs []error
+
+	ports := []struct{ name, value string }{
+		{"APP_PORT", c.AppPort},
+		{"GRPC_PORT", c.GRPCPort},
+		{"DB_PORT", c.DBPort},
+	}
+	if c.HealthPort != "" {
+		ports = append(ports, struct{ name, value string }{"HEALTH_PORT", c.HealthPort})
+		if c.HealthPort == c.AppPort || c.HealthPort == c.GRPCPort {
+			errs = append(errs, errors.New("HEALTH_PORT must differ from APP_PORT and GRPC_PORT"))
+		}
+	}
+	for _, port := range ports {
+		if p, err := strconv.Atoi(port.value); err != nil || p <= 0 || p > 65535 {
+			errs = append(errs, fmt.Errorf("%s: invalid port %q", port.name, port.value))
+		}
+	}
+	if c.DBHost == "" {
+		errs = append(errs, errors.New("DB_HOST is required"))
+	}
+	if c.DBName == "" {
+		errs = append(errs, errors.New("DB_NAME is required"))
+	}
+	switch c.DBSSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		errs = append(errs, fmt.Errorf("DB_SSLMODE: unknown mode %q", c.DBSSLMode))
 	}
-	return fallback
+	if c.DBMaxOpenConns < 0 || c.DBMaxIdleConns < 0 {
+		errs = append(errs, errors.New("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS must not be negative"))
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		errs = append(errs, errors.New("DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS"))
+	}
+	if c.DBConnectTimeout < 0 {
+		errs = append(errs, errors.New("DB_CONNECT_TIMEOUT must not be negative"))
+	}
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: unknown level %q", c.LogLevel))
+	}
+	switch c.LogFormat {
+	case "json", "console":
+	default:
+		errs = append(errs, fmt.Errorf("LOG_FORMAT: unknown format %q", c.LogFormat))
+	}
+	switch c.BrokerType {
+	case "":
+	case "kafka", "nats":
+		if c.BrokerURL == "" {
+			errs = append(errs, errors.New("BROKER_URL is required when BROKER_TYPE is set"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("BROKER_TYPE: unknown broker %q", c.BrokerType))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if c.ShutdownDelay < 0 {
+		errs = append(errs, errors.New("SHUTDOWN_DELAY must not be negative"))
+	}
+	if c.TraceSampleRatio < 0 || c.TraceSampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("OTEL_TRACES_SAMPLER_ARG: ratio %v is out of [0, 1]", c.TraceSampleRatio))
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	switch c.TLSClientAuth {
+	case "none":
+	case "optional", "require":
+		if c.TLSCertFile == "" {
+			errs = append(errs, errors.New("TLS_CLIENT_AUTH requires TLS_CERT_FILE and TLS_KEY_FILE"))
+		}
+		if c.TLSClientCAFile == "" {
+			errs = append(errs, errors.New("TLS_CLIENT_CA_FILE is required when TLS_CLIENT_AUTH is set"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("TLS_CLIENT_AUTH: unknown mode %q", c.TLSClientAuth))
+	}
+	identities, err := certs.ParseIdentities(c.TLSClientIdentities)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("TLS_CLIENT_IDENTITIES: %w", err))
+	}
+	services := slices.Collect(maps.Values(identities))
+	for _, admin := range c.AdminIdentities {
+		if !slices.Contains(services, admin) {
+			errs = append(errs, fmt.Errorf("ADMIN_IDENTITIES: %q is not listed in TLS_CLIENT_IDENTITIES", admin))
+		}
+	}
+	for _, user := range c.UserIdentities {
+		if !slices.Contains(services, user) {
+			errs = append(errs, fmt.Errorf("USER_IDENTITIES: %q is not listed in TLS_CLIENT_IDENTITIES", user))
+		}
+	}
+	if c.MaxBodyBytes < 0 {
+		errs = append(errs, errors.New("MAX_BODY_BYTES must not be negative"))
+	}
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: %q is neither an IP address nor a CIDR", proxy))
+			}
+		}
+	}
+	for _, origin := range c.CORSAllowedOrigins {
+		switch {
+		case origin == "*":
+			if c.CORSAllowCredentials {
+				errs = append(errs, errors.New(`CORS_ALLOWED_ORIGINS: "*" cannot be combined with CORS_ALLOW_CREDENTIALS`))
+			}
+		case !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://"):
+			errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS: origin %q must start with http:// or https://", origin))
+		}
+	}
+	switch c.RateLimitBackend {
+	case "memory":
+	case "redis":
+		if c.RateLimitRedisURL == "" {
+			errs = append(errs, errors.New("RATE_LIMIT_REDIS_URL is required when RATE_LIMIT_BACKEND is redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_BACKEND: unknown backend %q", c.RateLimitBackend))
+	}
+	limits := []struct{ name, value string }{
+		{"RATE_LIMIT_API", c.RateLimitAPI},
+		{"RATE_LIMIT_TOTAL", c.RateLimitTotal},
+		{"RATE_LIMIT_GRAPHQL", c.RateLimitGraphQL},
+	}
+	for _, limit := range limits {
+		if _, err := ratelimit.ParseLimit(limit.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", limit.name, err))
+		}
+	}
+	if c.WebhookMaxAttempts <= 0 {
+		errs = append(errs, errors.New("WEBHOOK_MAX_ATTEMPTS must be positive"))
+	}
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// BindFlags регистрирует флаг на каждый параметр: DB_HOST → --db-host.
+func BindFlags(fs *pflag.FlagSet) {
+	for _, s := range (&Config{}).settings() {
+		fs.String(flagName(s.key), "", "переопределяет "+s.key)
+	}
+}
+
+// FlagValues возвращает только флаги, заданные явно, по ключам параметров.
+func FlagValues(fs *pflag.FlagSet) map[string]string {
+	values := make(map[string]string)
+	for _, s := range (&Config{}).settings() {
+		if f := fs.Lookup(flagName(s.key)); f != nil && f.Changed {
+			values[s.key] = f.Value.String()
+		}
+	}
+	return values
+}
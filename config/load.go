@@ -0,0 +1,217 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Источники значений в порядке возрастания приоритета.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceDotEnv  = ".env"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+type LoadOptions struct {
+	// File — YAML файл конфигурации. Пусто — берётся CONFIG_FILE, если задан.
+	File string
+	// DotEnv — путь к .env; его значения слабее переменных окружения.
+	DotEnv string
+	// Flags — значения флагов командной строки по ключу (DB_HOST и т.п.).
+	Flags map[string]string
+}
+
+// Load собирает конфигурацию: значения по умолчанию, файл, .env, окружение,
+// флаги — каждый следующий источник перекрывает предыдущий. Для любого
+// параметра можно задать KEY_FILE с путём к файлу (Docker secrets), тогда
+// значение читается из файла. Все ошибки разбора возвращаются вместе; при
+// них возвращается и конфигурация, чтобы Validate дополнил список ошибок.
+func Load(opts LoadOptions) (*Config, error) {
+	layers, err := readLayers(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{sources: make(map[string]string), loadOptions: opts}
+	var errs []error
+	for _, s := range cfg.settings() {
+		value, source := s.def, SourceDefault
+		for _, layer := range layers {
+			if v, ok, err := layer.lookup(s.key); err != nil {
+				errs = append(errs, err)
+			} else if ok {
+				value, source = v, layer.name
+			}
+		}
+
+		if err := parseInto(s.target, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s (from %s): %w", s.key, source, err))
+		}
+		cfg.sources[s.key] = source
+	}
+
+	return cfg, errors.Join(errs...)
+}
+
+// Reload заново читает те же источники, например по SIGHUP.
+func (c *Config) Reload() (*Config, error) {
+	return Load(c.loadOptions)
+}
+
+type layer struct {
+	name   string
+	values map[string]string
+}
+
+// lookup ищет ключ в слое; KEY_FILE в том же слое имеет приоритет над KEY.
+func (l layer) lookup(key string) (string, bool, error) {
+	if path, ok := l.values[key+"_FILE"]; ok && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("%s_FILE (from %s): %w", key, l.name, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), true, nil
+	}
+	value, ok := l.values[key]
+	return value, ok, nil
+}
+
+func readLayers(opts LoadOptions) ([]layer, error) {
+	var layers []layer
+
+	file := opts.File
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		values, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer{name: SourceFile, values: values})
+	}
+
+	if opts.DotEnv != "" {
+		values, err := godotenv.Read(opts.DotEnv)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s: %w", opts.DotEnv, err)
+		}
+		layers = append(layers, layer{name: SourceDotEnv, values: values})
+	}
+
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	layers = append(layers, layer{name: SourceEnv, values: env})
+
+	if len(opts.Flags) > 0 {
+		layers = append(layers, layer{name: SourceFlag, values: opts.Flags})
+	}
+	return layers, nil
+}
+
+// readFile читает YAML файл с плоскими ключами в нижнем регистре:
+// db_host: db, db_replica_hosts: [replica1, replica2], http_read_timeout: 15s.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	known := make(map[string]bool)
+	for _, s := range (&Config{}).settings() {
+		known[s.key] = true
+		known[s.key+"_FILE"] = true
+	}
+
+	values := make(map[string]string, len(raw))
+	var errs []error
+	for key, value := range raw {
+		upper := strings.ToUpper(key)
+		if !known[upper] {
+			errs = append(errs, fmt.Errorf("config file %s: unknown key %q", path, key))
+			continue
+		}
+		switch v := value.(type) {
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[upper] = strings.Join(items, ",")
+		case nil:
+			values[upper] = ""
+		default:
+			values[upper] = fmt.Sprint(v)
+		}
+	}
+	return values, errors.Join(errs...)
+}
+
+func parseInto(target interface{}, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *int:
+		if value == "" {
+			*t = 0
+			return nil
+		}
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		*t = i
+	case *float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		*t = f
+	case *bool:
+		if value == "" {
+			*t = false
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		*t = b
+	case *time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		*t = d
+	case *[]string:
+		*t = nil
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*t = append(*t, item)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported setting type %T", target)
+	}
+	return nil
+}
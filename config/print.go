@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "******"
+
+// WriteYAML печатает итоговую конфигурацию в формате файла конфигурации.
+// Секреты скрыты, в комментарии указан источник каждого значения.
+func (c *Config) WriteYAML(w io.Writer) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range c.settings() {
+		value := formatValue(s.target)
+		if s.secret && value != "" {
+			value = redacted
+		}
+
+		doc.Content = append(doc.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: strings.ToLower(s.key)},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value, Style: scalarStyle(s.target, value), LineComment: c.sources[s.key]},
+		)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func formatValue(target interface{}) string {
+	switch t := target.(type) {
+	case *string:
+		return *t
+	case *int:
+		return strconv.Itoa(*t)
+	case *float64:
+		return strconv.FormatFloat(*t, 'g', -1, 64)
+	case *bool:
+		return strconv.FormatBool(*t)
+	case *time.Duration:
+		return t.String()
+	case *[]string:
+		return strings.Join(*t, ",")
+	default:
+		return fmt.Sprint(target)
+	}
+}
+
+// scalarStyle заключает строки в кавычки, чтобы "8080" или "" не стали
+// числом или null при повторном чтении файла.
+func scalarStyle(target interface{}, value string) yaml.Style {
+	switch target.(type) {
+	case *string, *[]string:
+		return yaml.DoubleQuotedStyle
+	}
+	if value == "" {
+		return yaml.DoubleQuotedStyle
+	}
+	return 0
+}
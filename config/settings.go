@@ -0,0 +1,92 @@
+package config
+
+// setting описывает один параметр: имя переменной окружения (оно же, в нижнем
+// регистре, ключ в файле конфигурации и, через дефисы, имя флага), поле
+// Config и значение по умолчанию.
+type setting struct {
+	key    string
+	target interface{}
+	def    string
+	secret bool
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{key: "APP_PORT", target: &c.AppPort, def: "8080"},
+		{key: "GRPC_PORT", target: &c.GRPCPort, def: "9090"},
+		{key: "HEALTH_PORT", target: &c.HealthPort},
+
+		{key: "DB_HOST", target: &c.DBHost, def: "localhost"},
+		{key: "DB_PORT", target: &c.DBPort, def: "5432"},
+		{key: "DB_USER", target: &c.DBUser, def: "postgres"},
+		{key: "DB_PASSWORD", target: &c.DBPassword, def: "postgres", secret: true},
+		{key: "DB_NAME", target: &c.DBName, def: "subscription_db"},
+		{key: "DB_SSLMODE", target: &c.DBSSLMode, def: "disable"},
+		{key: "DB_REPLICA_HOSTS", target: &c.DBReplicaHosts},
+		{key: "DB_MAX_OPEN_CONNS", target: &c.DBMaxOpenConns, def: "25"},
+		{key: "DB_MAX_IDLE_CONNS", target: &c.DBMaxIdleConns, def: "10"},
+		{key: "DB_CONN_MAX_LIFETIME", target: &c.DBConnMaxLifetime, def: "30m"},
+		{key: "DB_CONN_MAX_IDLE_TIME", target: &c.DBConnMaxIdleTime, def: "5m"},
+		{key: "DB_CONNECT_TIMEOUT", target: &c.DBConnectTimeout, def: "1m"},
+
+		{key: "LOG_LEVEL", target: &c.LogLevel, def: "info"},
+		{key: "LOG_FORMAT", target: &c.LogFormat, def: "json"},
+		{key: "LOG_FILE", target: &c.LogFile},
+		{key: "LOG_MAX_SIZE_MB", target: &c.LogMaxSizeMB, def: "100"},
+		{key: "LOG_MAX_BACKUPS", target: &c.LogMaxBackups, def: "5"},
+		{key: "LOG_MAX_AGE_DAYS", target: &c.LogMaxAgeDays, def: "30"},
+
+		{key: "HTTP_READ_TIMEOUT", target: &c.HTTPReadTimeout, def: "15s"},
+		{key: "HTTP_READ_HEADER_TIMEOUT", target: &c.HTTPReadHeaderTimeout, def: "5s"},
+		{key: "HTTP_WRITE_TIMEOUT", target: &c.HTTPWriteTimeout, def: "30s"},
+		{key: "HTTP_IDLE_TIMEOUT", target: &c.HTTPIdleTimeout, def: "2m"},
+		{key: "SHUTDOWN_TIMEOUT", target: &c.ShutdownTimeout, def: "20s"},
+		{key: "SHUTDOWN_DELAY", target: &c.ShutdownDelay, def: "5s"},
+		{key: "HEALTH_CHECK_TIMEOUT", target: &c.HealthCheckTimeout, def: "2s"},
+		{key: "MAX_BODY_BYTES", target: &c.MaxBodyBytes, def: "1048576"},
+		{key: "HSTS_MAX_AGE", target: &c.HSTSMaxAge, def: "8760h"},
+		{key: "TRUSTED_PROXIES", target: &c.TrustedProxies},
+
+		{key: "CORS_ALLOWED_ORIGINS", target: &c.CORSAllowedOrigins},
+		{key: "CORS_ALLOWED_METHODS", target: &c.CORSAllowedMethods, def: "GET,POST,PUT,PATCH,DELETE"},
+		{key: "CORS_ALLOWED_HEADERS", target: &c.CORSAllowedHeaders, def: "Content-Type,Authorization,X-API-Key,X-Request-ID"},
+		{key: "CORS_ALLOW_CREDENTIALS", target: &c.CORSAllowCredentials, def: "false"},
+		{key: "CORS_MAX_AGE", target: &c.CORSMaxAge, def: "12h"},
+
+		{key: "WEBHOOK_MAX_ATTEMPTS", target: &c.WebhookMaxAttempts, def: "8"},
+		{key: "WEBHOOK_TIMEOUT", target: &c.WebhookTimeout, def: "10s"},
+		{key: "WEBHOOK_POLL_INTERVAL", target: &c.WebhookPollInterval, def: "5s"},
+
+		{key: "BROKER_TYPE", target: &c.BrokerType},
+		{key: "BROKER_URL", target: &c.BrokerURL, secret: true},
+		{key: "BROKER_TOPIC", target: &c.BrokerTopic, def: "subscriptions"},
+		{key: "OUTBOX_POLL_INTERVAL", target: &c.OutboxPollInterval, def: "1s"},
+		{key: "OUTBOX_RETENTION", target: &c.OutboxRetention, def: "168h"},
+		{key: "PRICE_CHANGE_INTERVAL", target: &c.PriceChangeInterval, def: "1h"},
+		{key: "INSIGHTS_INTERVAL", target: &c.InsightsInterval, def: "6h"},
+
+		{key: "GRAPHQL_MAX_COMPLEXITY", target: &c.GraphQLMaxComplexity, def: "1000"},
+		{key: "GRAPHQL_MAX_DEPTH", target: &c.GraphQLMaxDepth, def: "8"},
+
+		{key: "ADMIN_TOKEN", target: &c.AdminToken, secret: true},
+		{key: "ADMIN_IDENTITIES", target: &c.AdminIdentities},
+		{key: "USER_IDENTITIES", target: &c.UserIdentities},
+
+		{key: "TLS_CERT_FILE", target: &c.TLSCertFile},
+		{key: "TLS_KEY_FILE", target: &c.TLSKeyFile},
+		{key: "TLS_CLIENT_CA_FILE", target: &c.TLSClientCAFile},
+		{key: "TLS_CLIENT_AUTH", target: &c.TLSClientAuth, def: "none"},
+		{key: "TLS_CLIENT_IDENTITIES", target: &c.TLSClientIdentities},
+		{key: "TLS_RELOAD_INTERVAL", target: &c.TLSReloadInterval, def: "30s"},
+
+		{key: "RATE_LIMIT_BACKEND", target: &c.RateLimitBackend, def: "memory"},
+		{key: "RATE_LIMIT_REDIS_URL", target: &c.RateLimitRedisURL, secret: true},
+		{key: "RATE_LIMIT_API", target: &c.RateLimitAPI, def: "300/1m"},
+		{key: "RATE_LIMIT_TOTAL", target: &c.RateLimitTotal, def: "30/1m"},
+		{key: "RATE_LIMIT_GRAPHQL", target: &c.RateLimitGraphQL, def: "60/1m"},
+
+		{key: "OTEL_SERVICE_NAME", target: &c.ServiceName, def: "subscribers"},
+		{key: "OTEL_EXPORTER_OTLP_ENDPOINT", target: &c.OTLPEndpoint},
+		{key: "OTEL_TRACES_SAMPLER_ARG", target: &c.TraceSampleRatio, def: "1"},
+	}
+}
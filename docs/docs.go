@@ -15,9 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/createSubscription": {
+        "/admin/catalog/services": {
             "post": {
-                "description": "Добавляет новую подписку пользователя на сервис. Возвращает 409 при дублировании.",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Каноническое название и алиасы сравниваются без учёта регистра и лишних пробелов; каждое написание может принадлежать только одному сервису.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,23 +30,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "catalog"
                 ],
-                "summary": "Создаёт новую подписку",
+                "summary": "Добавить сервис в каталог",
                 "parameters": [
                     {
-                        "description": "Данные для создания подписки",
+                        "description": "Запись каталога",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateSubscriptionRequest"
+                            "$ref": "#/definitions/models.CatalogServiceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Подписка успешно создана",
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogService"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -49,8 +60,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Неверные данные запроса",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -59,7 +70,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Подписка уже существует",
+                        "description": "Название или алиас уже в каталоге",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -68,7 +79,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -79,8 +90,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions": {
-            "get": {
+        "/admin/catalog/services/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Алиасы и тарифы заменяются целиком. При смене названия подписки, сведённые к сервису, переименовываются.",
                 "consumes": [
                     "application/json"
                 ],
@@ -88,26 +105,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "catalog"
                 ],
-                "summary": "Получить список подписок по user_id",
+                "summary": "Заменить запись каталога",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID пользователя (UUID)",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "ID сервиса (UUID)",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Запись каталога",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogServiceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.SubscriptionSwagger"
-                            }
+                            "$ref": "#/definitions/models.CatalogService"
                         }
                     },
                     "400": {
@@ -119,6 +142,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Название или алиас уже в каталоге",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -129,54 +179,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/subscriptions/total": {
-            "get": {
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
                 ],
+                "description": "Подписки сохраняют название и перестают ссылаться на каталог.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "catalog"
                 ],
-                "summary": "Получить суммарную стоимость подписок за период с фильтрацией",
+                "summary": "Удалить запись каталога",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID пользователя (UUID)",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "ID сервиса (UUID)",
+                        "name": "id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Название подписки (фильтр)",
-                        "name": "service_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Начало периода (формат: 01-2006)",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Конец периода (формат: 01-2006)",
-                        "name": "end_date",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Суммарная стоимость",
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "integer"
+                                "type": "string"
                             }
                         }
                     },
@@ -189,6 +222,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -201,42 +252,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions/{id}": {
+        "/admin/log-level": {
             "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Глобальный уровень и уровни компонентов, заданные отдельно.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
-                ],
-                "summary": "Получить одну подписку по ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID подписки (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Текущий уровень логирования",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SubscriptionSwagger"
+                            "$ref": "#/definitions/models.LogLevelResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Меняет глобальный уровень или уровень компонента (handlers, services, db) без перезапуска. С revert_after прежний уровень вернётся автоматически.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Изменить уровень логирования",
+                "parameters": [
+                    {
+                        "description": "Новый уровень",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LogLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogLevelResponse"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -244,8 +329,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -254,34 +339,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/budgets": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "budget"
                 ],
-                "summary": "Удалить подписку по ID",
+                "summary": "Получить бюджеты пользователя",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID подписки (UUID)",
-                        "name": "id",
-                        "in": "path",
+                        "description": "ID пользователя (UUID)",
+                        "name": "user_id",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Budget"
                             }
                         }
                     },
@@ -294,8 +390,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Нет доступа в организации",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -314,8 +410,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Для удаления даты окончания подписки необходимо передать пустую строку в поле ` + "`" + `ended_at` + "`" + `.",
+            "post": {
+                "description": "Бюджет на все подписки (overall), одну категорию (category) или один сервис (service). Название сервиса сводится к каталогу. Когда создание или изменение подписки выводит бюджет за лимит в одном из ближайших 12 месяцев, публикуется событие budget.exceeded.",
                 "consumes": [
                     "application/json"
                 ],
@@ -323,30 +419,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "budget"
                 ],
-                "summary": "Обновить подписку",
+                "summary": "Создать месячный бюджет",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID подписки (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные для обновления",
-                        "name": "subscription",
+                        "description": "Бюджет",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSubscriptionRequest"
+                            "$ref": "#/definitions/models.BudgetRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -354,8 +461,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Нет доступа в организации",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -363,8 +470,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Бюджет с такой областью уже есть",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -383,44 +490,3100 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.CreateSubscriptionRequest": {
-            "type": "object",
-            "required": [
-                "price",
-                "service_name",
-                "start_date",
-                "user_id"
-            ],
-            "properties": {
-                "end_date": {
-                    "description": "Дата окончания подписки (формат: 2006-01 или 01-2006)\nrequired: false",
-                    "type": "string"
-                },
-                "price": {
-                    "description": "Цена подписки (мин 0)\nrequired: true",
-                    "type": "integer",
-                    "minimum": 0
-                },
+        },
+        "/budgets/status": {
+            "get": {
+                "description": "Для каждого бюджета и месяца — прогноз расходов по действующим в месяце подпискам (по разделённым — доля пользователя) и остаток. Без start_date период начинается с текущего месяца, без end_date — заканчивается месяцем начала; не больше 120 месяцев.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budget"
+                ],
+                "summary": "Исполнение бюджетов по месяцам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID)",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (формат: 01-2006)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (формат: 01-2006)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BudgetStatus"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budget"
+                ],
+                "summary": "Удалить бюджет",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бюджета (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budget"
+                ],
+                "summary": "Изменить лимит бюджета",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бюджета (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый лимит",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateBudgetRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/services": {
+            "get": {
+                "description": "Канонические названия, алиасы и тарифы с ценами по умолчанию.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalog"
+                ],
+                "summary": "Получить каталог сервисов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Категория (фильтр)",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CatalogService"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/services/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalog"
+                ],
+                "summary": "Получить запись каталога по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID сервиса (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogService"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/createSubscription": {
+            "post": {
+                "description": "Добавляет новую подписку пользователя на сервис. Возвращает 409 при дублировании.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Создаёт новую подписку",
+                "parameters": [
+                    {
+                        "description": "Данные для создания подписки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Подписка успешно создана",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные запроса",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка уже существует",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Тело запроса больше MAX_BODY_BYTES",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "description": "Подписки пользователей, расходы по месяцам и по сервисам за один запрос. Запросы со сложностью или глубиной выше лимита отклоняются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "GraphQL endpoint",
+                "parameters": [
+                    {
+                        "description": "GraphQL запрос",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graphqlapi.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Процесс запущен и обрабатывает запросы. Зависимости не проверяются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/insights": {
+            "get": {
+                "description": "Повышения цены (price_increase), подписки на один сервис каталога под разными названиями (duplicate), подписки без изменений больше двух лет (forgotten) и месяцы, когда расходы выросли в полтора раза против среднего за три предыдущих (spending_spike). Подписки анализируются в фоне раз в INSIGHTS_INTERVAL.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "insight"
+                ],
+                "summary": "Находки анализа подписок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID)",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "price_increase",
+                            "duplicate",
+                            "forgotten",
+                            "spending_spike"
+                        ],
+                        "type": "string",
+                        "description": "Вид находок",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть и скрытые находки",
+                        "name": "include_dismissed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Insight"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/insights/{id}/dismiss": {
+            "post": {
+                "description": "Скрытая находка не возвращается по умолчанию и не создаётся анализом повторно.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "insight"
+                ],
+                "summary": "Скрыть находку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID находки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "post": {
+                "description": "Пользователь из заголовка X-User-ID становится администратором организации. X-User-ID принимается только от сервисов из USER_IDENTITIES.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Создать организацию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные организации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{org_id}/members": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Получить участников организации",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID)",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Membership"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{org_id}/members/{user_id}": {
+            "put": {
+                "description": "Доступно администраторам организации. Последний администратор не может понизить себя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Добавить участника или изменить его роль",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID)",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID участника (UUID)",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID администратора (UUID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Роль участника",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Membership"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Подписки участника остаются в организации.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Исключить участника из организации",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID)",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID участника (UUID)",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID администратора (UUID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{org_id}/totals": {
+            "get": {
+                "description": "Доступно администраторам. Период фильтруется по месяцу начала подписки, как в /subscriptions/total.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Суммы подписок организации по участникам и сервисам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID)",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID администратора (UUID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (формат: 01-2006)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (формат: 01-2006)",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationTotals"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Проверяет базу данных, применённые миграции и фоновые воркеры. Возвращает 503, если хотя бы одна проверка не прошла.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Получить список подписок по user_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID)",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию — все подписки)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение от начала списка",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "entertainment",
+                            "software",
+                            "utilities",
+                            "education",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Категория (фильтр)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег (фильтр); можно повторить — подписка должна иметь все теги",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SubscriptionSwagger"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/forecast": {
+            "get": {
+                "description": "Прогноз по действующим подпискам с учётом дат окончания, периода оплаты и запланированных изменений цены. billed — списания в месяце (годовая подписка списывается раз в 12 месяцев), accrued — помесячная стоимость, cumulative — списания нарастающим итогом. По разделённым подпискам учитывается доля пользователя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Прогноз расходов на ближайшие месяцы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID)",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество месяцев (по умолчанию 3, не больше 120)",
+                        "name": "months",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый месяц прогноза (формат: 01-2006), по умолчанию следующий",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Forecast"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/settlements": {
+            "get": {
+                "description": "Для каждого месяца периода — кто кому сколько должен с участием пользователя, после взаимозачёта. Без start_date период начинается с самой ранней подписки, без end_date — заканчивается текущим месяцем; не больше 120 месяцев.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Взаиморасчёты по разделённым подпискам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID)",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (формат: 01-2006)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (формат: 01-2006)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MonthlySettlement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/total": {
+            "get": {
+                "description": "По разделённым подпискам учитывается только доля пользователя, в том числе по подпискам, которые оплачивает другой участник. С group_by ответ дополняется суммами по группам; подписка с несколькими тегами входит в каждую их группу, пустой key — подписки без категории или без тегов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Получить суммарную стоимость подписок за период с фильтрацией",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID)",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название подписки (фильтр)",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "entertainment",
+                            "software",
+                            "utilities",
+                            "education",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Категория (фильтр)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег (фильтр); можно повторить — подписка должна иметь все теги",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "service",
+                            "category",
+                            "tag"
+                        ],
+                        "type": "string",
+                        "description": "Группировка",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (формат: 01-2006)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (формат: 01-2006)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Суммарная стоимость",
+                        "schema": {
+                            "$ref": "#/definitions/models.SpendingBreakdown"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Получить одну подписку по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionSwagger"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Удалить подписку по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Для удаления даты окончания подписки необходимо передать пустую строку в поле ` + "`" + `ended_at` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Обновить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка на этот сервис уже есть",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/members": {
+            "put": {
+                "description": "Заменяет участников подписки. Плательщик — владелец подписки, ему достаётся остаток после долей участников. Правила: equal — поровну, percent — проценты участников (в сумме не больше 100), fixed — фиксированные суммы (в сумме не больше стоимости). Пустой список участников отменяет разделение.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Разделить подписку между пользователями",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Правило и участники",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetMembersRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionSwagger"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа в организации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/price-changes": {
+            "get": {
+                "description": "Запланированные и уже применённые (с applied_at) изменения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Изменения цены подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Новая цена действует с effective_date (не раньше следующего месяца) и учитывается в прогнозе. Когда месяц наступает, цена подписки меняется и публикуется событие subscription.price_changed. Повторное изменение на тот же месяц заменяет прежнее.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Запланировать изменение цены подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая цена",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SchedulePriceChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/price-changes/{change_id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Отменить запланированное изменение цены",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID подписки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID изменения (UUID)",
+                        "name": "change_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена или изменение уже применено",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Получить список webhook endpoint'ов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookEndpoint"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Webhook'ами организации управляют только администраторы",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Endpoint получает события пространства, в котором зарегистрирован: организации из X-Organization-ID или личного. События подписываются HMAC-SHA256 секретом и передаются в заголовке X-Webhook-Signature (формат sha256=\u003chex\u003e).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Зарегистрировать webhook endpoint",
+                "parameters": [
+                    {
+                        "description": "Данные endpoint'а",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWebhookRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Webhook'ами организации управляют только администраторы",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/failed": {
+            "get": {
+                "description": "Доставки, исчерпавшие все попытки отправки.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Dead-letter очередь webhook'ов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Webhook'ами организации управляют только администраторы",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/redeliver": {
+            "post": {
+                "description": "Возвращает доставку в очередь со сброшенным счётчиком попыток.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Повторно отправить webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID доставки (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Webhook'ами организации управляют только администраторы",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Удалить webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID endpoint'а (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID организации (UUID); без него — личное пространство",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Webhook'ами организации управляют только администраторы",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "graphqlapi.Request": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BudgetMonth": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "type": "string",
+                    "example": "2024-01"
+                },
+                "over": {
+                    "type": "boolean"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "spent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "scope",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Лимит в месяц\nrequired: true",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "scope": {
+                    "description": "Область: overall, category или service\nrequired: true",
+                    "type": "string",
+                    "enum": [
+                        "overall",
+                        "category",
+                        "service"
+                    ]
+                },
+                "target": {
+                    "description": "Категория (для category) или название сервиса (для service)",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "ID пользователя в формате UUID\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "$ref": "#/definitions/models.Budget"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetMonth"
+                    }
+                }
+            }
+        },
+        "models.CatalogService": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ServiceAlias"
+                    }
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "plans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ServicePlan"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogServiceRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "aliases": {
+                    "description": "Другие написания названия; регистр и лишние пробелы не учитываются",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "description": "Категория: entertainment, software, utilities, education или other",
+                    "type": "string",
+                    "enum": [
+                        "entertainment",
+                        "software",
+                        "utilities",
+                        "education",
+                        "other"
+                    ]
+                },
+                "name": {
+                    "description": "Каноническое название\nrequired: true",
+                    "type": "string"
+                },
+                "plans": {
+                    "description": "Тарифы с ценами по умолчанию",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ServicePlanRequest"
+                    }
+                },
+                "website": {
+                    "description": "Сайт сервиса",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateOrganizationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Название организации\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "service_name",
+                "start_date",
+                "user_id"
+            ],
+            "properties": {
+                "billing_period": {
+                    "description": "Период оплаты в месяцах: 1, 3, 6 или 12 (по умолчанию 1); price — цена за месяц\nrequired: false",
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        3,
+                        6,
+                        12
+                    ]
+                },
+                "category": {
+                    "description": "Категория: entertainment, software, utilities, education или other; без неё берётся категория из каталога\nrequired: false",
+                    "type": "string",
+                    "enum": [
+                        "entertainment",
+                        "software",
+                        "utilities",
+                        "education",
+                        "other"
+                    ]
+                },
+                "end_date": {
+                    "description": "Дата окончания подписки (формат: 2006-01 или 01-2006)\nrequired: false",
+                    "type": "string"
+                },
+                "plan": {
+                    "description": "Тариф сервиса из каталога\nrequired: false",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Цена подписки (мин 0); без неё берётся цена тарифа plan из каталога\nrequired: false",
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "service_name": {
                     "description": "Название сервиса\nrequired: true",
                     "type": "string"
                 },
-                "start_date": {
-                    "description": "Дата начала подписки (формат: 2006-01 или 01-2006)\nrequired: true",
+                "start_date": {
+                    "description": "Дата начала подписки (формат: 2006-01 или 01-2006)\nrequired: true",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Произвольные теги\nrequired: false",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "ID пользователя в формате UUID\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "Список событий (subscription.created, subscription.updated, subscription.deleted, subscription.ended, subscription.price_changed, budget.exceeded). Пустой — все события\nrequired: false",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Секрет для HMAC-SHA256 подписи тела запроса\nrequired: true",
+                    "type": "string",
+                    "minLength": 16
+                },
+                "url": {
+                    "description": "URL, на который отправляются события\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Debt": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Forecast": {
+            "type": "object",
+            "properties": {
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ForecastMonth"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ForecastMonth": {
+            "type": "object",
+            "properties": {
+                "accrued": {
+                    "type": "integer"
+                },
+                "billed": {
+                    "type": "integer"
+                },
+                "cumulative": {
+                    "type": "integer"
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-01"
+                }
+            }
+        },
+        "models.GroupSpending": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "subscriptions": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Insight": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dismissed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "previous_amount": {
+                    "type": "integer"
+                },
+                "related_subscription_id": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LogLevelRequest": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "description": "Component — handlers, services или db; пусто — глобальный уровень.",
+                    "type": "string",
+                    "example": "db"
+                },
+                "level": {
+                    "description": "Level — debug, info, warn или error. Пустой уровень у компонента\nвозвращает его к глобальному.",
+                    "type": "string",
+                    "example": "debug"
+                },
+                "revert_after": {
+                    "description": "RevertAfter — через сколько вернуть прежний уровень, например 15m.",
+                    "type": "string",
+                    "example": "15m"
+                }
+            }
+        },
+        "models.LogLevelResponse": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "level": {
+                    "type": "string",
+                    "example": "info"
+                }
+            }
+        },
+        "models.MemberShareRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Фиксированная сумма в месяц (для fixed)",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "percent": {
+                    "description": "Доля в процентах (для percent)",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "user_id": {
+                    "description": "ID пользователя в формате UUID\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Membership": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "role": {
                     "type": "string"
                 },
                 "user_id": {
-                    "description": "ID пользователя в формате UUID\nrequired: true",
                     "type": "string"
                 }
             }
         },
+        "models.MonthlySettlement": {
+            "type": "object",
+            "properties": {
+                "debts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Debt"
+                    }
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2024-01"
+                }
+            }
+        },
+        "models.Organization": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrganizationTotals": {
+            "type": "object",
+            "properties": {
+                "by_service": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ServiceSpending"
+                    }
+                },
+                "by_user": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserSpending"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PriceChange": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "previous_price": {
+                    "description": "PreviousPrice — цена до применения; nil у ещё не применённых.",
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SchedulePriceChangeRequest": {
+            "type": "object",
+            "required": [
+                "effective_date",
+                "price"
+            ],
+            "properties": {
+                "effective_date": {
+                    "description": "Месяц, с которого действует новая цена (формат: 01-2006), не раньше следующего\nrequired: true",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Новая цена в месяц\nrequired: true",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.ServiceAlias": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ServicePlan": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ServicePlanRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Название тарифа\nrequired: true",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Цена в месяц",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.ServiceSpending": {
+            "type": "object",
+            "properties": {
+                "service_name": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SetMemberRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "description": "Роль: admin или member\nrequired: true",
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "member"
+                    ]
+                }
+            }
+        },
+        "models.SetMembersRequest": {
+            "type": "object",
+            "required": [
+                "split_rule"
+            ],
+            "properties": {
+                "members": {
+                    "description": "Участники без плательщика\nrequired: false",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MemberShareRequest"
+                    }
+                },
+                "split_rule": {
+                    "description": "Правило разделения: equal, percent или fixed. Пустой список участников отменяет разделение\nrequired: true",
+                    "type": "string",
+                    "enum": [
+                        "equal",
+                        "percent",
+                        "fixed"
+                    ]
+                }
+            }
+        },
+        "models.SpendingBreakdown": {
+            "type": "object",
+            "properties": {
+                "group_by": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GroupSpending"
+                    }
+                },
+                "total_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SubscriptionMemberSwagger": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "percent": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174003"
+                }
+            }
+        },
         "models.SubscriptionSwagger": {
             "type": "object",
             "properties": {
+                "billing_period": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "category": {
+                    "type": "string",
+                    "example": "entertainment"
+                },
                 "ended_at": {
                     "type": "string",
                     "example": "06-2024"
@@ -429,27 +3592,94 @@ const docTemplate = `{
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubscriptionMemberSwagger"
+                    }
+                },
                 "monthly_price": {
                     "type": "integer",
                     "example": 1000
                 },
+                "org_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174002"
+                },
+                "service_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174004"
+                },
                 "service_name": {
                     "type": "string",
                     "example": "Netflix"
                 },
+                "split_rule": {
+                    "type": "string",
+                    "example": "equal"
+                },
                 "started_at": {
                     "type": "string",
                     "example": "01-2024"
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "family",
+                        "weekend"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-06-15T10:00:00Z"
+                },
                 "user_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174001"
                 }
             }
         },
+        "models.UpdateBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Лимит в месяц\nrequired: true",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
         "models.UpdateSubscriptionRequest": {
             "type": "object",
             "properties": {
+                "billing_period": {
+                    "description": "Период оплаты в месяцах: 1, 3, 6 или 12",
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        3,
+                        6,
+                        12
+                    ]
+                },
+                "category": {
+                    "description": "Категория; пустая строка убирает категорию",
+                    "type": "string",
+                    "enum": [
+                        "entertainment",
+                        "software",
+                        "utilities",
+                        "education",
+                        "other",
+                        ""
+                    ]
+                },
                 "end_date": {
                     "description": "Дата окончания подписки (формат: 01-2006)",
                     "type": "string"
@@ -465,9 +3695,100 @@ const docTemplate = `{
                 "start_date": {
                     "description": "Дата начала подписки (формат: 01-2006)",
                     "type": "string"
+                },
+                "tags": {
+                    "description": "Теги; заменяют текущие, пустой список убирает все",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.UserSpending": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "endpoint_id": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookEndpoint": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "description": "OrgID — организация, события которой получает endpoint; nil — события\nличного пространства.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Bearer \u003cADMIN_TOKEN\u003e",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
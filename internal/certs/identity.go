@@ -0,0 +1,38 @@
+package certs
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// Identities сопоставляет субъект клиентского сертификата (Common Name или
+// DNS имя из SAN) с именем сервиса, которое используется для авторизации.
+type Identities map[string]string
+
+// ParseIdentities разбирает записи вида "billing.internal=billing".
+func ParseIdentities(entries []string) (Identities, error) {
+	ids := make(Identities, len(entries))
+	for _, entry := range entries {
+		subject, identity, ok := strings.Cut(entry, "=")
+		subject, identity = strings.TrimSpace(subject), strings.TrimSpace(identity)
+		if !ok || subject == "" || identity == "" {
+			return nil, fmt.Errorf("invalid identity mapping %q, expected <subject>=<identity>", entry)
+		}
+		ids[subject] = identity
+	}
+	return ids, nil
+}
+
+// Resolve находит сервис для сертификата: сначала по Common Name, затем по DNS SAN.
+func (ids Identities) Resolve(cert *x509.Certificate) (string, bool) {
+	if identity, ok := ids[cert.Subject.CommonName]; ok {
+		return identity, true
+	}
+	for _, name := range cert.DNSNames {
+		if identity, ok := ids[name]; ok {
+			return identity, true
+		}
+	}
+	return "", false
+}
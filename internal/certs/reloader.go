@@ -0,0 +1,147 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"subscribers/logger"
+	"sync/atomic"
+	"time"
+)
+
+// ClientAuth — режим проверки клиентских сертификатов.
+type ClientAuth string
+
+const (
+	ClientAuthNone     ClientAuth = "none"
+	ClientAuthOptional ClientAuth = "optional"
+	ClientAuthRequire  ClientAuth = "require"
+)
+
+type Options struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile — CA для проверки клиентских сертификатов (mTLS).
+	ClientCAFile string
+	ClientAuth   ClientAuth
+}
+
+// Reloader держит актуальную TLS конфигурацию и перечитывает сертификаты,
+// когда файлы на диске меняются. Уже установленные соединения продолжают
+// работать со старым сертификатом.
+type Reloader struct {
+	opts    Options
+	current atomic.Pointer[tls.Config]
+	stamp   string
+}
+
+// NewReloader загружает сертификат, ключ и CA. Ошибка при старте фатальна,
+// при последующих перезагрузках остаётся предыдущая конфигурация.
+func NewReloader(opts Options) (*Reloader, error) {
+	r := &Reloader{opts: opts}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig возвращает конфигурацию для HTTP и gRPC серверов: каждое новое
+// соединение получает последнюю загруженную версию сертификатов.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Watch проверяет файлы раз в interval, пока не отменён ctx.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stamp, err := r.fileStamp()
+			if err != nil {
+				logger.SugaredLogger.Errorf("TLS: failed to check certificate files: %v", err)
+				continue
+			}
+			if stamp == r.stamp {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				// Повторять загрузку тех же файлов бессмысленно: ждём следующего изменения.
+				r.stamp = stamp
+				logger.SugaredLogger.Errorf("TLS: failed to reload certificates, keeping the previous ones: %v", err)
+				continue
+			}
+			logger.SugaredLogger.Info("TLS: certificates reloaded")
+		}
+	}
+}
+
+func (r *Reloader) reload() error {
+	stamp, err := r.fileStamp()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load key pair: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if r.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("client CA file contains no certificates")
+		}
+		config.ClientCAs = pool
+	}
+	switch r.opts.ClientAuth {
+	case ClientAuthOptional:
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	r.current.Store(config)
+	r.stamp = stamp
+	return nil
+}
+
+// fileStamp — размер и время изменения всех файлов. Замена файла (в том числе
+// подмена symlink, как при обновлении секретов в Kubernetes) меняет отпечаток.
+func (r *Reloader) fileStamp() (string, error) {
+	var stamp string
+	for _, path := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return stamp, nil
+}
@@ -1,30 +1,124 @@
 package db
 
 import (
+	"context"
+	"fmt"
 	"subscribers/logger"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
-func ConnectGORM(dsn string) *gorm.DB {
-	logger.SugaredLogger.Info("Connecting to the database...")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// replicaResolver — имя резолвера, через который идут запросы на чтение,
+// допускающие отставание реплики (списки и суммы).
+const replicaResolver = "replica"
+
+type Options struct {
+	DSN         string
+	ReplicaDSNs []string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// MaxWait — сколько ждать готовности базы при старте. 0 — одна попытка.
+	MaxWait time.Duration
+}
+
+// ConnectGORM подключается к базе, повторяя попытки с экспоненциальной
+// задержкой, пока база не ответит, не истечёт MaxWait или не будет отменён ctx.
+func ConnectGORM(ctx context.Context, opts Options) *gorm.DB {
+	log := logger.Component(logger.ComponentDB)
+	log.Info("Connecting to the database...")
+	db, err := gorm.Open(postgres.Open(opts.DSN), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               newGormLogger(),
+	})
 	if err != nil {
-		logger.SugaredLogger.Errorf("Couldn't connect to PostgreSQL: %v", err)
+		log.Errorf("Couldn't connect to PostgreSQL: %v", err)
 		return nil
 	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		logger.SugaredLogger.Errorf("Couldn't get sql.DB: %v", err)
+		log.Errorf("Couldn't get sql.DB: %v", err)
 		return nil
 	}
-	if err = sqlDB.Ping(); err != nil {
-		logger.SugaredLogger.Errorf("Ping to the database failed: %v", err)
+	sqlDB.SetMaxOpenConns(opts.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(opts.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+
+	if err = waitForDB(ctx, db, opts.MaxWait); err != nil {
+		log.Errorf("Ping to the database failed: %v", err)
+		sqlDB.Close()
 		return nil
 	}
 
-	logger.SugaredLogger.Info("GORM is connected to PostgreSQL")
+	if len(opts.ReplicaDSNs) > 0 {
+		if err = registerReplicas(db, opts); err != nil {
+			log.Errorf("Couldn't register read replicas: %v", err)
+			sqlDB.Close()
+			return nil
+		}
+		log.Infof("Read queries are routed to %d replica(s)", len(opts.ReplicaDSNs))
+	}
+
+	log.Info("GORM is connected to PostgreSQL")
 	return db
 }
+
+func waitForDB(ctx context.Context, db *gorm.DB, maxWait time.Duration) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(maxWait)
+	delay := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err = sqlDB.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if ctx.Err() != nil || remaining <= 0 {
+			return fmt.Errorf("gave up after %d attempt(s): %w", attempt, err)
+		}
+
+		wait := min(delay, remaining)
+		logger.Component(logger.ComponentDB).Warnf("Database is not ready (attempt %d), retrying in %s: %v", attempt, wait, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, 10*time.Second)
+	}
+}
+
+// registerReplicas подключает реплики как именованный резолвер: запросы
+// попадают на них только через ReadReplica, остальные читают из primary.
+// Подключение к репликам ленивое, их недоступность не мешает старту.
+func registerReplicas(db *gorm.DB, opts Options) error {
+	replicas := make([]gorm.Dialector, 0, len(opts.ReplicaDSNs))
+	for _, dsn := range opts.ReplicaDSNs {
+		replicas = append(replicas, postgres.Open(dsn))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{Replicas: replicas}, replicaResolver).
+		SetMaxOpenConns(opts.MaxOpenConns).
+		SetMaxIdleConns(opts.MaxIdleConns).
+		SetConnMaxLifetime(opts.ConnMaxLifetime).
+		SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+	return db.Use(resolver)
+}
+
+// ReadReplica направляет запрос на реплику, если она настроена.
+// Без реплик запрос выполняется на primary как обычно.
+func ReadReplica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(replicaResolver))
+}
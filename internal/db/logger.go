@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"subscribers/logger"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold — запросы дольше этого порога пишутся в лог как медленные.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// zapLogger направляет сообщения GORM в логгер запроса, чтобы они были в
+// том же JSON формате и с теми же request_id/trace_id.
+type zapLogger struct {
+	level gormlogger.LogLevel
+}
+
+func newGormLogger() gormlogger.Interface {
+	return zapLogger{level: gormlogger.Warn}
+}
+
+func (l zapLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	l.level = level
+	return l
+}
+
+func (l zapLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		dbLogger(ctx).Infof(msg, args...)
+	}
+}
+
+func (l zapLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		dbLogger(ctx).Warnf(msg, args...)
+	}
+}
+
+func (l zapLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		dbLogger(ctx).Errorf(msg, args...)
+	}
+}
+
+// Trace пишет ошибки и медленные запросы, а на уровне debug компонента db —
+// каждый SQL запрос.
+func (l zapLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	log := dbLogger(ctx)
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		log.Errorw("SQL query failed", "error", err, "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		log.Warnw("Slow SQL query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	case log.Level().Enabled(zapcore.DebugLevel):
+		sql, rows := fc()
+		log.Debugw("SQL query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	}
+}
+
+func dbLogger(ctx context.Context) *zap.SugaredLogger {
+	return logger.ForComponent(logger.FromContext(ctx), logger.ComponentDB)
+}
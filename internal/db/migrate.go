@@ -6,8 +6,68 @@ import (
 	"gorm.io/gorm"
 )
 
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+// Models — все таблицы сервиса в порядке создания.
+func Models() []interface{} {
+	return []interface{}{
+		&models.CatalogService{},
+		&models.ServiceAlias{},
+		&models.ServicePlan{},
+		&models.Organization{},
+		&models.Membership{},
 		&models.Subscription{},
-	)
+		&models.SubscriptionMember{},
+		&models.SubscriptionTag{},
+		&models.PriceChange{},
+		&models.Budget{},
+		&models.Insight{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
+		&models.OutboxEvent{},
+	}
+}
+
+func AutoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(Models()...)
+}
+
+// MigrateDown удаляет таблицы сервиса в обратном порядке.
+func MigrateDown(db *gorm.DB) error {
+	all := Models()
+	for i := len(all) - 1; i >= 0; i-- {
+		if err := db.Migrator().DropTable(all[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type TableStatus struct {
+	Table          string
+	Exists         bool
+	MissingColumns []string
+}
+
+// MigrationStatus сравнивает схему базы с моделями.
+func MigrationStatus(db *gorm.DB) ([]TableStatus, error) {
+	var result []TableStatus
+	for _, model := range Models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+
+		status := TableStatus{
+			Table:  stmt.Schema.Table,
+			Exists: db.Migrator().HasTable(model),
+		}
+		if status.Exists {
+			for _, field := range stmt.Schema.Fields {
+				if field.DBName != "" && !db.Migrator().HasColumn(model, field.DBName) {
+					status.MissingColumns = append(status.MissingColumns, field.DBName)
+				}
+			}
+		}
+		result = append(result, status)
+	}
+	return result, nil
 }
@@ -0,0 +1,140 @@
+package graphqlapi
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listMultiplier — во сколько раз дороже считаются поля внутри списка.
+const listMultiplier = 10
+
+type Limits struct {
+	MaxComplexity int
+	MaxDepth      int
+}
+
+type complexityWalker struct {
+	fragments map[string]*ast.FragmentDefinition
+	limits    Limits
+	// visiting — фрагменты на текущем пути обхода. Проверка выполняется до
+	// валидации graphql-go, поэтому циклы фрагментов нужно ловить здесь:
+	// иначе обход уходит в бесконечную рекурсию.
+	visiting map[string]bool
+}
+
+// checkComplexity оценивает стоимость запроса до выполнения: каждое поле
+// стоит 1, поля внутри списков умножаются на listMultiplier.
+func checkComplexity(schema graphql.Schema, doc *ast.Document, limits Limits) error {
+	w := complexityWalker{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		limits:    limits,
+		visiting:  make(map[string]bool),
+	}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			w.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		root := schema.QueryType()
+		if op.Operation == ast.OperationTypeMutation {
+			root = schema.MutationType()
+		}
+		cost, err := w.selectionCost(root, op.SelectionSet, 1)
+		if err != nil {
+			return err
+		}
+		if limits.MaxComplexity > 0 && cost > limits.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the limit of %d", cost, limits.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+func (w complexityWalker) selectionCost(parent *graphql.Object, set *ast.SelectionSet, depth int) (int, error) {
+	if set == nil || parent == nil {
+		return 0, nil
+	}
+	if w.limits.MaxDepth > 0 && depth > w.limits.MaxDepth {
+		return 0, fmt.Errorf("query depth exceeds the limit of %d", w.limits.MaxDepth)
+	}
+
+	cost := 0
+	for _, selection := range set.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			fieldCost, err := w.fieldCost(parent, sel, depth)
+			if err != nil {
+				return 0, err
+			}
+			cost += fieldCost
+		case *ast.InlineFragment:
+			c, err := w.selectionCost(parent, sel.SelectionSet, depth)
+			if err != nil {
+				return 0, err
+			}
+			cost += c
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			fragment, ok := w.fragments[name]
+			if !ok {
+				continue
+			}
+			if w.visiting[name] {
+				return 0, fmt.Errorf("fragment %q spreads itself", name)
+			}
+			w.visiting[name] = true
+			c, err := w.selectionCost(parent, fragment.SelectionSet, depth)
+			delete(w.visiting, name)
+			if err != nil {
+				return 0, err
+			}
+			cost += c
+		}
+		// Фрагменты можно разворачивать многократно, поэтому обход прекращается,
+		// как только лимит превышен, а не после подсчёта всего запроса.
+		if w.limits.MaxComplexity > 0 && cost > w.limits.MaxComplexity {
+			return 0, fmt.Errorf("query complexity exceeds the limit of %d", w.limits.MaxComplexity)
+		}
+	}
+	return cost, nil
+}
+
+func (w complexityWalker) fieldCost(parent *graphql.Object, field *ast.Field, depth int) (int, error) {
+	def, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 1, nil
+	}
+
+	fieldType := def.Type
+	isList := false
+	for {
+		if nonNull, ok := fieldType.(*graphql.NonNull); ok {
+			fieldType = nonNull.OfType
+			continue
+		}
+		if list, ok := fieldType.(*graphql.List); ok {
+			isList = true
+			fieldType = list.OfType
+			continue
+		}
+		break
+	}
+
+	object, _ := fieldType.(*graphql.Object)
+	childCost, err := w.selectionCost(object, field.SelectionSet, depth+1)
+	if err != nil {
+		return 0, err
+	}
+	if isList {
+		childCost *= listMultiplier
+	}
+	return 1 + childCost, nil
+}
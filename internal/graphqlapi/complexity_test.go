@@ -0,0 +1,76 @@
+package graphqlapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestCheckComplexity(t *testing.T) {
+	schema, err := NewSchema(nil)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	limits := Limits{MaxComplexity: 100, MaxDepth: 4}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "simple",
+			query: `{ user(id: "1") { id subscriptions { serviceName } } }`,
+		},
+		{
+			name:  "fragment",
+			query: `{ user(id: "1") { ...F } } fragment F on User { id }`,
+		},
+		{
+			name:    "list multiplier",
+			query:   `{ users(ids: ["1"]) { subscriptions { serviceName monthlyPrice } } }`,
+			wantErr: "complexity",
+		},
+		{
+			name:    "too deep",
+			query:   `{ user(id: "1") { subscriptions { user { subscriptions { user { id } } } } } }`,
+			wantErr: "depth",
+		},
+		{
+			name:    "fragment spreads itself",
+			query:   `query { ...A } fragment A on Query { ...A }`,
+			wantErr: `fragment "A" spreads itself`,
+		},
+		{
+			name:    "fragment cycle",
+			query:   `query { ...A } fragment A on Query { ...B } fragment B on Query { ...A }`,
+			wantErr: "spreads itself",
+		},
+		{
+			name: "fragment fan-out",
+			query: `{ ...A } fragment A on Query { ...B ...B ...B } fragment B on Query { ...C ...C ...C }
+				fragment C on Query { ...D ...D ...D } fragment D on Query { ...E ...E ...E }
+				fragment E on Query { user(id: "1") { id } }`,
+			wantErr: "complexity",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			err = checkComplexity(schema, doc, limits)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkComplexity: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkComplexity = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package graphqlapi
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"gorm.io/gorm"
+)
+
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+type Executor struct {
+	db     *gorm.DB
+	schema graphql.Schema
+	limits Limits
+}
+
+func NewExecutor(db *gorm.DB, limits Limits) (*Executor, error) {
+	schema, err := NewSchema(db)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{db: db, schema: schema, limits: limits}, nil
+}
+
+// Execute проверяет сложность запроса и выполняет его с собственным loader'ом,
+// чтобы кеш подписок не переживал запрос.
+func (e *Executor) Execute(ctx context.Context, req Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}}
+	}
+
+	if err := checkComplexity(e.schema, doc, e.limits); err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}}
+	}
+
+	return graphql.Do(graphql.Params{
+		Schema:         e.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        withLoader(ctx, e.db.WithContext(ctx)),
+	})
+}
@@ -0,0 +1,91 @@
+package graphqlapi
+
+import (
+	"context"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"sync"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	loaderKey         struct{}
+	spendingLoaderKey struct{}
+)
+
+// fetchFunc загружает подписки нескольких пользователей одним запросом.
+type fetchFunc func(db *gorm.DB, tenant models.Tenant, userIDs []uuid.UUID) (map[uuid.UUID][]models.Subscription, error)
+
+// subscriptionsLoader собирает user_id из всех резолверов одного уровня запроса
+// и загружает их подписки одним SELECT ... WHERE user_id IN (...).
+// Резолверы возвращают thunk, graphql-go вычисляет их после обхода уровня,
+// поэтому к моменту первого вызова все ключи уже зарегистрированы.
+type subscriptionsLoader struct {
+	db      *gorm.DB
+	tenant  models.Tenant
+	fetch   fetchFunc
+	mu      sync.Mutex
+	pending map[uuid.UUID]struct{}
+	cache   map[uuid.UUID][]models.Subscription
+}
+
+// withLoader кладёт в контекст два загрузчика: подписок, которые пользователь
+// оплачивает (User.subscriptions), и подписок с его долями для расчёта
+// расходов — как в /subscriptions/total.
+func withLoader(ctx context.Context, db *gorm.DB) context.Context {
+	tenant := services.TenantFrom(ctx)
+	ctx = context.WithValue(ctx, loaderKey{}, newLoader(db, tenant, services.GetSubscriptionsByUsers))
+	return context.WithValue(ctx, spendingLoaderKey{}, newLoader(db, tenant, services.GetSubscriptionsInvolvingUsers))
+}
+
+func newLoader(db *gorm.DB, tenant models.Tenant, fetch fetchFunc) *subscriptionsLoader {
+	return &subscriptionsLoader{
+		db:      db,
+		tenant:  tenant,
+		fetch:   fetch,
+		pending: make(map[uuid.UUID]struct{}),
+		cache:   make(map[uuid.UUID][]models.Subscription),
+	}
+}
+
+func loaderFrom(ctx context.Context) *subscriptionsLoader {
+	return ctx.Value(loaderKey{}).(*subscriptionsLoader)
+}
+
+func spendingLoaderFrom(ctx context.Context) *subscriptionsLoader {
+	return ctx.Value(spendingLoaderKey{}).(*subscriptionsLoader)
+}
+
+func (l *subscriptionsLoader) Load(userID uuid.UUID) func() ([]models.Subscription, error) {
+	l.mu.Lock()
+	if _, ok := l.cache[userID]; !ok {
+		l.pending[userID] = struct{}{}
+	}
+	l.mu.Unlock()
+
+	return func() ([]models.Subscription, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if subs, ok := l.cache[userID]; ok {
+			return subs, nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(l.pending))
+		for id := range l.pending {
+			ids = append(ids, id)
+		}
+		l.pending = make(map[uuid.UUID]struct{})
+
+		loaded, err := l.fetch(l.db, l.tenant, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id, subs := range loaded {
+			l.cache[id] = subs
+		}
+		return l.cache[userID], nil
+	}
+}
@@ -0,0 +1,325 @@
+package graphqlapi
+
+import (
+	"errors"
+	"fmt"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"gorm.io/gorm"
+)
+
+var yearMonthType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "YearMonth",
+	Fields: graphql.Fields{
+		"year": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.YearMonth).Year, nil
+			},
+		},
+		"month": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return int(p.Source.(models.YearMonth).Month), nil
+			},
+		},
+		"value": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Месяц в формате 2006-01",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.YearMonth).String(), nil
+			},
+		},
+	},
+})
+
+var monthlySpendingType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "MonthlySpending",
+	Fields: graphql.Fields{
+		"month": &graphql.Field{
+			Type: graphql.NewNonNull(yearMonthType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.MonthlySpending).Month, nil
+			},
+		},
+		"total": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.MonthlySpending).Total, nil
+			},
+		},
+	},
+})
+
+var serviceSpendingType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ServiceSpending",
+	Fields: graphql.Fields{
+		"serviceName": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.ServiceSpending).ServiceName, nil
+			},
+		},
+		"total": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.ServiceSpending).Total, nil
+			},
+		},
+	},
+})
+
+var periodArgs = graphql.FieldConfigArgument{
+	"startDate": &graphql.ArgumentConfig{Type: graphql.String, Description: "Начало периода (01-2006 или 2006-01)"},
+	"endDate":   &graphql.ArgumentConfig{Type: graphql.String, Description: "Конец периода (01-2006 или 2006-01)"},
+}
+
+var subscriptionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Subscription",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.Subscription).ID.String(), nil
+			},
+		},
+		"serviceName": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.Subscription).ServiceName, nil
+			},
+		},
+		"category": &graphql.Field{
+			Type:        graphql.String,
+			Description: "entertainment, software, utilities, education или other; null без категории",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				category := p.Source.(models.Subscription).Category
+				if category == "" {
+					return nil, nil
+				}
+				return category, nil
+			},
+		},
+		"orgId": &graphql.Field{
+			Type:        graphql.ID,
+			Description: "Организация-владелец; null у подписок личного пространства",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				orgID := p.Source.(models.Subscription).OrgID
+				if orgID == nil {
+					return nil, nil
+				}
+				return orgID.String(), nil
+			},
+		},
+		"monthlyPrice": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.Subscription).MonthlyPrice, nil
+			},
+		},
+		"startedAt": &graphql.Field{
+			Type: graphql.NewNonNull(yearMonthType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(models.Subscription).StartedAt, nil
+			},
+		},
+		"endedAt": &graphql.Field{
+			Type: yearMonthType,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				endedAt := p.Source.(models.Subscription).EndedAt
+				if endedAt == nil {
+					return nil, nil
+				}
+				return *endedAt, nil
+			},
+		},
+	},
+})
+
+var userType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "User",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(uuid.UUID).String(), nil
+			},
+		},
+		"subscriptions": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(subscriptionType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				load := loaderFrom(p.Context).Load(p.Source.(uuid.UUID))
+				return func() (interface{}, error) {
+					return load()
+				}, nil
+			},
+		},
+		"total": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Доли пользователя в подписках, начавшихся в периоде (как в /subscriptions/total)",
+			Args: graphql.FieldConfigArgument{
+				"serviceName": &graphql.ArgumentConfig{Type: graphql.String},
+				"startDate":   periodArgs["startDate"],
+				"endDate":     periodArgs["endDate"],
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				startYM, endYM, err := periodFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				serviceName, _ := p.Args["serviceName"].(string)
+				userID := p.Source.(uuid.UUID)
+				load := spendingLoaderFrom(p.Context).Load(userID)
+				return func() (interface{}, error) {
+					subs, err := load()
+					if err != nil {
+						return nil, err
+					}
+					total := 0
+					for _, spending := range services.CalculateServiceTotals(subs, userID, startYM, endYM) {
+						if serviceName == "" || spending.ServiceName == serviceName {
+							total += spending.Total
+						}
+					}
+					return total, nil
+				}, nil
+			},
+		},
+		"monthlySpending": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(monthlySpendingType))),
+			Description: "Доли пользователя по месяцам с учётом дат начала и окончания подписок",
+			Args:        periodArgs,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				startYM, endYM, err := periodFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				userID := p.Source.(uuid.UUID)
+				load := spendingLoaderFrom(p.Context).Load(userID)
+				return func() (interface{}, error) {
+					subs, err := load()
+					if err != nil {
+						return nil, err
+					}
+					return services.CalculateMonthlySpending(subs, userID, startYM, endYM)
+				}, nil
+			},
+		},
+		"serviceTotals": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(serviceSpendingType))),
+			Args: periodArgs,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				startYM, endYM, err := periodFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				userID := p.Source.(uuid.UUID)
+				load := spendingLoaderFrom(p.Context).Load(userID)
+				return func() (interface{}, error) {
+					subs, err := load()
+					if err != nil {
+						return nil, err
+					}
+					return services.CalculateServiceTotals(subs, userID, startYM, endYM), nil
+				}, nil
+			},
+		},
+	},
+})
+
+// Subscription.user добавляется после объявления обоих типов:
+// User.subscriptions и Subscription.user ссылаются друг на друга.
+func init() {
+	subscriptionType.AddFieldConfig("user", &graphql.Field{
+		Type: graphql.NewNonNull(userType),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(models.Subscription).UserID, nil
+		},
+	})
+}
+
+// NewSchema собирает схему. db используется для запросов, которым не нужен
+// батчинг; подписки пользователей грузятся через loader из контекста.
+func NewSchema(db *gorm.DB) (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"subscription": &graphql.Field{
+				Type: subscriptionType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := uuid.Parse(p.Args["id"].(string))
+					if err != nil {
+						return nil, fmt.Errorf("invalid subscription ID")
+					}
+					sub, err := services.GetSubscriptionByID(db.WithContext(p.Context), services.TenantFrom(p.Context), id)
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, err
+					}
+					return *sub, nil
+				},
+			},
+			"user": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := uuid.Parse(p.Args["id"].(string))
+					if err != nil {
+						return nil, fmt.Errorf("invalid UUID format")
+					}
+					return id, nil
+				},
+			},
+			"users": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+				Args: graphql.FieldConfigArgument{
+					"ids": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					rawIDs := p.Args["ids"].([]interface{})
+					ids := make([]uuid.UUID, 0, len(rawIDs))
+					for _, raw := range rawIDs {
+						id, err := uuid.Parse(raw.(string))
+						if err != nil {
+							return nil, fmt.Errorf("invalid UUID format: %s", raw)
+						}
+						ids = append(ids, id)
+					}
+					return ids, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func periodFromArgs(args map[string]interface{}) (*models.YearMonth, *models.YearMonth, error) {
+	var startYM, endYM *models.YearMonth
+	if s, ok := args["startDate"].(string); ok && s != "" {
+		ym, err := utils.ParseYearMonth(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid startDate format")
+		}
+		startYM = &ym
+	}
+	if s, ok := args["endDate"].(string); ok && s != "" {
+		ym, err := utils.ParseYearMonth(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid endDate format")
+		}
+		endYM = &ym
+	}
+	return startYM, endYM, nil
+}
@@ -0,0 +1,249 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	subscribersv1 "subscribers/api/subscribers/v1"
+	"subscribers/internal/certs"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/internal/utils"
+	"subscribers/logger"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Options — TLS и доверенные сервисы gRPC сервера; по смыслу совпадают с
+// настройками HTTP сервера.
+type Options struct {
+	// TLS включает TLS; без него сервер работает без шифрования.
+	TLS *tls.Config
+	// Identities сопоставляет клиентские сертификаты с сервисами (mTLS).
+	Identities certs.Identities
+	// UserIdentities — сервисы, которым доверяются метаданные x-user-id.
+	UserIdentities []string
+}
+
+// New создаёт gRPC сервер с SubscriptionService, health и reflection.
+func New(db *gorm.DB, opts Options) *grpc.Server {
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(tenantInterceptor(db, opts.Identities, opts.UserIdentities)),
+	}
+	if opts.TLS != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(opts.TLS)))
+	}
+	server := grpc.NewServer(serverOpts...)
+
+	subscribersv1.RegisterSubscriptionServiceServer(server, &subscriptionServer{db: db})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(subscribersv1.SubscriptionService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
+
+type subscriptionServer struct {
+	subscribersv1.UnimplementedSubscriptionServiceServer
+	db *gorm.DB
+}
+
+func (s *subscriptionServer) CreateSubscription(ctx context.Context, req *subscribersv1.CreateSubscriptionRequest) (*subscribersv1.CreateSubscriptionResponse, error) {
+	request := models.CreateSubscriptionRequest{
+		ServiceName:   req.GetServiceName(),
+		Price:         int(req.GetPrice()),
+		BillingPeriod: int(req.GetBillingPeriod()),
+		Plan:          req.GetPlan(),
+		Category:      req.GetCategory(),
+		Tags:          req.GetTags(),
+		UserID:        req.GetUserId(),
+		StartDate:     req.GetStartDate(),
+		EndDate:       req.EndDate,
+	}
+	if err := request.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	subID, err := services.CreateSubscription(s.db.WithContext(ctx), services.TenantFrom(ctx), request)
+	if err != nil {
+		logger.SugaredLogger.Warnf("gRPC: failed to create subscription: %v", err)
+		if errors.Is(err, services.ErrSubscriptionExists) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrNotMember) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &subscribersv1.CreateSubscriptionResponse{Id: subID.String()}, nil
+}
+
+func (s *subscriptionServer) GetSubscription(ctx context.Context, req *subscribersv1.GetSubscriptionRequest) (*subscribersv1.Subscription, error) {
+	subID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscription ID")
+	}
+
+	sub, err := services.GetSubscriptionByID(s.db.WithContext(ctx), services.TenantFrom(ctx), subID)
+	if err != nil {
+		return nil, toStatus(err, "failed to fetch subscription")
+	}
+
+	return toProto(*sub), nil
+}
+
+func (s *subscriptionServer) ListSubscriptions(ctx context.Context, req *subscribersv1.ListSubscriptionsRequest) (*subscribersv1.ListSubscriptionsResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid UUID format")
+	}
+
+	subscriptions, err := services.GetSubscriptions(s.db.WithContext(ctx), services.TenantFrom(ctx), userID, models.SubscriptionFilter{}, models.Pagination{})
+	if err != nil {
+		return nil, toStatus(err, "failed to fetch subscriptions")
+	}
+
+	resp := &subscribersv1.ListSubscriptionsResponse{
+		Subscriptions: make([]*subscribersv1.Subscription, 0, len(subscriptions)),
+	}
+	for _, sub := range subscriptions {
+		resp.Subscriptions = append(resp.Subscriptions, toProto(sub))
+	}
+	return resp, nil
+}
+
+func (s *subscriptionServer) UpdateSubscription(ctx context.Context, req *subscribersv1.UpdateSubscriptionRequest) (*subscribersv1.UpdateSubscriptionResponse, error) {
+	subID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscription ID")
+	}
+
+	update := models.UpdateSubscriptionRequest{
+		ServiceName: req.ServiceName,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		Category:    req.Category,
+	}
+	if req.Price != nil {
+		price := int(req.GetPrice())
+		update.Price = &price
+	}
+	if req.BillingPeriod != nil {
+		period := int(req.GetBillingPeriod())
+		update.BillingPeriod = &period
+	}
+	if req.Tags != nil {
+		tags := req.GetTags().GetValues()
+		update.Tags = &tags
+	}
+	if err := update.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := services.UpdateSubscription(s.db.WithContext(ctx), services.TenantFrom(ctx), subID, update); err != nil {
+		return nil, toStatus(err, "failed to update subscription")
+	}
+
+	return &subscribersv1.UpdateSubscriptionResponse{}, nil
+}
+
+func (s *subscriptionServer) DeleteSubscription(ctx context.Context, req *subscribersv1.DeleteSubscriptionRequest) (*subscribersv1.DeleteSubscriptionResponse, error) {
+	subID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscription ID")
+	}
+
+	if err := services.DeleteSubscription(s.db.WithContext(ctx), services.TenantFrom(ctx), subID); err != nil {
+		return nil, toStatus(err, "failed to delete subscription")
+	}
+
+	return &subscribersv1.DeleteSubscriptionResponse{}, nil
+}
+
+func (s *subscriptionServer) GetSubscriptionsTotal(ctx context.Context, req *subscribersv1.GetSubscriptionsTotalRequest) (*subscribersv1.GetSubscriptionsTotalResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid UUID format")
+	}
+
+	startYM, err := parseOptionalYearMonth(req.GetStartDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid start_date format")
+	}
+	endYM, err := parseOptionalYearMonth(req.GetEndDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid end_date format")
+	}
+
+	filter := models.SubscriptionFilter{ServiceName: req.GetServiceName(), StartYM: startYM, EndYM: endYM}
+	total, err := services.CalculateSubscriptionsTotal(s.db.WithContext(ctx), services.TenantFrom(ctx), userID, filter)
+	if err != nil {
+		return nil, toStatus(err, "failed to calculate total")
+	}
+
+	return &subscribersv1.GetSubscriptionsTotalResponse{TotalPrice: int64(total)}, nil
+}
+
+func parseOptionalYearMonth(s string) (*models.YearMonth, error) {
+	if s == "" {
+		return nil, nil
+	}
+	ym, err := utils.ParseYearMonth(s)
+	if err != nil {
+		return nil, err
+	}
+	return &ym, nil
+}
+
+// toStatus переводит ошибки сервисов в gRPC коды так же, как это делают REST хендлеры.
+func toStatus(err error, message string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, "subscription not found")
+	}
+	if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrNotMember) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, services.ErrSubscriptionExists) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.Is(err, services.ErrInvalidDate) || errors.Is(err, services.ErrInvalidSplit) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	logger.SugaredLogger.Errorf("gRPC: %s: %v", message, err)
+	return status.Error(codes.Internal, message)
+}
+
+func toProto(sub models.Subscription) *subscribersv1.Subscription {
+	pb := &subscribersv1.Subscription{
+		Id:            sub.ID.String(),
+		ServiceName:   sub.ServiceName,
+		MonthlyPrice:  int64(sub.MonthlyPrice),
+		UserId:        sub.UserID.String(),
+		StartedAt:     sub.StartedAt.String(),
+		Category:      sub.Category,
+		BillingPeriod: int32(sub.BillingPeriod),
+	}
+	for _, tag := range sub.Tags {
+		pb.Tags = append(pb.Tags, tag.Tag)
+	}
+	if sub.EndedAt != nil {
+		endedAt := sub.EndedAt.String()
+		pb.EndedAt = &endedAt
+	}
+	return pb
+}
@@ -0,0 +1,86 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"subscribers/internal/certs"
+	"subscribers/internal/services"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Метаданные с организацией и пользователем — аналог заголовков
+// X-Organization-ID и X-User-ID в REST API.
+const (
+	organizationMetadata = "x-organization-id"
+	userMetadata         = "x-user-id"
+)
+
+// tenantInterceptor определяет арендатора вызова и кладёт его в контекст.
+// Без x-organization-id вызов работает в личном пространстве. Как и в REST
+// API, x-user-id принимается только от сервисов userIdentities, подтверждённых
+// клиентским сертификатом.
+func tenantInterceptor(db *gorm.DB, identities certs.Identities, userIdentities []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		userStr := firstValue(md, userMetadata)
+		if userStr != "" {
+			if identity, ok := peerIdentity(ctx, identities); !ok || !slices.Contains(userIdentities, identity) {
+				return nil, status.Error(codes.Unauthenticated, userMetadata+" is accepted only from a trusted client certificate")
+			}
+		}
+
+		orgStr := firstValue(md, organizationMetadata)
+		if orgStr == "" {
+			return handler(ctx, req)
+		}
+
+		orgID, err := uuid.Parse(orgStr)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid organization ID")
+		}
+		userID, err := uuid.Parse(userStr)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, userMetadata+" metadata with user UUID is required")
+		}
+
+		tenant, err := services.ResolveTenant(db.WithContext(ctx), orgID, userID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, status.Error(codes.NotFound, "organization not found")
+		case errors.Is(err, services.ErrNotMember):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case err != nil:
+			return nil, status.Error(codes.Internal, "failed to resolve organization")
+		}
+		return handler(services.WithTenant(ctx, tenant), req)
+	}
+}
+
+// peerIdentity определяет сервис по проверенному клиентскому сертификату вызова.
+func peerIdentity(ctx context.Context, identities certs.Identities) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 {
+		return "", false
+	}
+	return identities.Resolve(info.State.VerifiedChains[0][0])
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
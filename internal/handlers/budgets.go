@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateBudget
+// @Summary      Создать месячный бюджет
+// @Description  Бюджет на все подписки (overall), одну категорию (category) или один сервис (service). Название сервиса сводится к каталогу. Когда создание или изменение подписки выводит бюджет за лимит в одном из ближайших 12 месяцев, публикуется событие budget.exceeded.
+// @Tags         budget
+// @Accept       json
+// @Produce      json
+// @Param        request body models.BudgetRequest true "Бюджет"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      201 {object} models.Budget
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
+// @Failure      409 {object} map[string]string "Бюджет с такой областью уже есть"
+// @Failure      500 {object} map[string]string
+// @Router       /budgets [post]
+func CreateBudgetHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Budget creation started")
+
+		var req models.BudgetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Warnf("Bad request when creating a budget: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		budget, err := services.CreateBudget(db.WithContext(c.Request.Context()), tenant, req)
+		switch {
+		case accessDenied(c, err):
+			log.Warnf("Budget creation denied: %v", err)
+		case errors.Is(err, services.ErrBudgetExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err != nil:
+			log.Warnf("Failed to create budget: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Info("Budget creation success")
+			c.JSON(http.StatusCreated, budget)
+		}
+	}
+}
+
+// GetBudgets
+// @Summary      Получить бюджеты пользователя
+// @Tags         budget
+// @Produce      json
+// @Param        user_id query string true "ID пользователя (UUID)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {array} models.Budget
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
+// @Failure      500 {object} map[string]string
+// @Router       /budgets [get]
+func GetBudgetsHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		userID, err := uuid.Parse(c.Query("user_id"))
+		if err != nil {
+			log.Warnf("Invalid user_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required and must be a UUID"})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		budgets, err := services.GetBudgets(db.WithContext(c.Request.Context()), tenant, userID)
+		if accessDenied(c, err) {
+			log.Warnf("Get budgets denied: %v", err)
+			return
+		}
+		if err != nil {
+			log.Errorf("Error getting budgets: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budgets"})
+			return
+		}
+
+		c.JSON(http.StatusOK, budgets)
+	}
+}
+
+// UpdateBudget
+// @Summary      Изменить лимит бюджета
+// @Tags         budget
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID бюджета (UUID)"
+// @Param        request body models.UpdateBudgetRequest true "Новый лимит"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} models.Budget
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /budgets/{id} [patch]
+func UpdateBudgetHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid budget ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+			return
+		}
+
+		var req models.UpdateBudgetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Warnf("Bad request when updating a budget: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		budget, err := services.UpdateBudget(db.WithContext(c.Request.Context()), tenant, id, req)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+			return
+		}
+		if err != nil {
+			log.Errorf("Failed to update budget: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update budget"})
+			return
+		}
+
+		log.Infof("Budget %s updated", id)
+		c.JSON(http.StatusOK, budget)
+	}
+}
+
+// DeleteBudget
+// @Summary      Удалить бюджет
+// @Tags         budget
+// @Produce      json
+// @Param        id path string true "ID бюджета (UUID)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /budgets/{id} [delete]
+func DeleteBudgetHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid budget ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		if err := services.DeleteBudget(db.WithContext(c.Request.Context()), tenant, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+			} else {
+				log.Errorf("Failed to delete budget: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete budget"})
+			}
+			return
+		}
+
+		log.Infof("Budget %s deleted", id)
+		c.JSON(http.StatusOK, gin.H{"message": "budget deleted successfully"})
+	}
+}
+
+// GetBudgetStatus
+// @Summary      Исполнение бюджетов по месяцам
+// @Description  Для каждого бюджета и месяца — прогноз расходов по действующим в месяце подпискам (по разделённым — доля пользователя) и остаток. Без start_date период начинается с текущего месяца, без end_date — заканчивается месяцем начала; не больше 120 месяцев.
+// @Tags         budget
+// @Produce      json
+// @Param        user_id query string true "ID пользователя (UUID)"
+// @Param        start_date query string false "Начало периода (формат: 01-2006)"
+// @Param        end_date query string false "Конец периода (формат: 01-2006)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {array} models.BudgetStatus
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
+// @Failure      500 {object} map[string]string
+// @Router       /budgets/status [get]
+func GetBudgetStatusHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		userID, err := uuid.Parse(c.Query("user_id"))
+		if err != nil {
+			log.Warnf("Invalid user_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required and must be a UUID"})
+			return
+		}
+
+		startYM, endYM, err := parsePeriod(c)
+		if err != nil {
+			log.Warnf("Bad request when getting budget status: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		statuses, err := services.GetBudgetStatus(db.WithContext(c.Request.Context()), tenant, userID, startYM, endYM)
+		if accessDenied(c, err) {
+			log.Warnf("Get budget status denied: %v", err)
+			return
+		}
+		if errors.Is(err, services.ErrPeriodTooLong) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Errorf("Failed to calculate budget status: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to calculate budget status"})
+			return
+		}
+
+		c.JSON(http.StatusOK, statuses)
+	}
+}
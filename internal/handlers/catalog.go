@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetCatalogServices
+// @Summary      Получить каталог сервисов
+// @Description  Канонические названия, алиасы и тарифы с ценами по умолчанию.
+// @Tags         catalog
+// @Produce      json
+// @Param        category query string false "Категория (фильтр)"
+// @Success      200 {array} models.CatalogService
+// @Failure      500 {object} map[string]string
+// @Router       /catalog/services [get]
+func GetCatalogServicesHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		catalog, err := services.GetCatalogServices(db.WithContext(c.Request.Context()), c.Query("category"))
+		if err != nil {
+			log.Errorf("Error getting service catalog: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch service catalog"})
+			return
+		}
+
+		c.JSON(http.StatusOK, catalog)
+	}
+}
+
+// GetCatalogService
+// @Summary      Получить запись каталога по ID
+// @Tags         catalog
+// @Produce      json
+// @Param        id path string true "ID сервиса (UUID)"
+// @Success      200 {object} models.CatalogService
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /catalog/services/{id} [get]
+func GetCatalogServiceHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid catalog service ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service ID"})
+			return
+		}
+
+		service, err := services.GetCatalogService(db.WithContext(c.Request.Context()), id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+			return
+		}
+		if err != nil {
+			log.Errorf("Error getting catalog service: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch service"})
+			return
+		}
+
+		c.JSON(http.StatusOK, service)
+	}
+}
+
+// CreateCatalogService
+// @Summary      Добавить сервис в каталог
+// @Description  Каноническое название и алиасы сравниваются без учёта регистра и лишних пробелов; каждое написание может принадлежать только одному сервису.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body models.CatalogServiceRequest true "Запись каталога"
+// @Success      201 {object} models.CatalogService
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      409 {object} map[string]string "Название или алиас уже в каталоге"
+// @Failure      500 {object} map[string]string
+// @Router       /admin/catalog/services [post]
+func CreateCatalogServiceHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		var req models.CatalogServiceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Warnf("Bad request when creating a catalog service: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		service, err := services.CreateCatalogService(db.WithContext(c.Request.Context()), req)
+		if catalogConflict(c, err) {
+			return
+		}
+		if err != nil {
+			log.Errorf("Failed to create catalog service: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create service"})
+			return
+		}
+
+		log.Infof("Catalog service %q created", service.Name)
+		c.JSON(http.StatusCreated, service)
+	}
+}
+
+// UpdateCatalogService
+// @Summary      Заменить запись каталога
+// @Description  Алиасы и тарифы заменяются целиком. При смене названия подписки, сведённые к сервису, переименовываются.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "ID сервиса (UUID)"
+// @Param        request body models.CatalogServiceRequest true "Запись каталога"
+// @Success      200 {object} models.CatalogService
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Название или алиас уже в каталоге"
+// @Failure      500 {object} map[string]string
+// @Router       /admin/catalog/services/{id} [put]
+func UpdateCatalogServiceHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid catalog service ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service ID"})
+			return
+		}
+
+		var req models.CatalogServiceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Warnf("Bad request when updating a catalog service: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		service, err := services.UpdateCatalogService(db.WithContext(c.Request.Context()), id, req)
+		switch {
+		case catalogConflict(c, err):
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		case err != nil:
+			log.Errorf("Failed to update catalog service: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update service"})
+		default:
+			log.Infof("Catalog service %s updated", id)
+			c.JSON(http.StatusOK, service)
+		}
+	}
+}
+
+// DeleteCatalogService
+// @Summary      Удалить запись каталога
+// @Description  Подписки сохраняют название и перестают ссылаться на каталог.
+// @Tags         catalog
+// @Produce      json
+// @Security     AdminToken
+// @Param        id path string true "ID сервиса (UUID)"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/catalog/services/{id} [delete]
+func DeleteCatalogServiceHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid catalog service ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service ID"})
+			return
+		}
+
+		if err := services.DeleteCatalogService(db.WithContext(c.Request.Context()), id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+			} else {
+				log.Errorf("Failed to delete catalog service: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete service"})
+			}
+			return
+		}
+
+		log.Infof("Catalog service %s deleted", id)
+		c.JSON(http.StatusOK, gin.H{"message": "service deleted successfully"})
+	}
+}
+
+// catalogConflict отвечает 409, если название или алиас занят другим сервисом.
+func catalogConflict(c *gin.Context, err error) bool {
+	if errors.Is(err, services.ErrAliasTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return true
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/internal/utils"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const defaultForecastMonths = 3
+
+// GetForecast
+// @Summary      Прогноз расходов на ближайшие месяцы
+// @Description  Прогноз по действующим подпискам с учётом дат окончания, периода оплаты и запланированных изменений цены. billed — списания в месяце (годовая подписка списывается раз в 12 месяцев), accrued — помесячная стоимость, cumulative — списания нарастающим итогом. По разделённым подпискам учитывается доля пользователя.
+// @Tags         subscription
+// @Produce      json
+// @Param        user_id query string true "ID пользователя (UUID)"
+// @Param        months query int false "Количество месяцев (по умолчанию 3, не больше 120)"
+// @Param        start_date query string false "Первый месяц прогноза (формат: 01-2006), по умолчанию следующий"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} models.Forecast
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
+// @Failure      500 {object} map[string]string
+// @Router       /subscriptions/forecast [get]
+func GetForecastHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		userID, err := uuid.Parse(c.Query("user_id"))
+		if err != nil {
+			log.Warnf("Invalid user_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required and must be a UUID"})
+			return
+		}
+
+		months := defaultForecastMonths
+		if s := c.Query("months"); s != "" {
+			months, err = strconv.Atoi(s)
+			if err != nil || months < 1 {
+				log.Warnf("Invalid months: %q", s)
+				c.JSON(http.StatusBadRequest, gin.H{"error": "months must be a positive number"})
+				return
+			}
+		}
+
+		var startYM *models.YearMonth
+		if s := c.Query("start_date"); s != "" {
+			ym, err := utils.ParseYearMonth(s)
+			if err != nil {
+				log.Warnf("Invalid start_date format: %v", err)
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format"})
+				return
+			}
+			startYM = &ym
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		forecast, err := services.GetForecast(db.WithContext(c.Request.Context()), tenant, userID, startYM, months)
+		if accessDenied(c, err) {
+			log.Warnf("Get forecast denied: %v", err)
+			return
+		}
+		if errors.Is(err, services.ErrPeriodTooLong) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Errorf("Failed to calculate forecast: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to calculate forecast"})
+			return
+		}
+
+		c.JSON(http.StatusOK, forecast)
+	}
+}
+
+// SchedulePriceChange
+// @Summary      Запланировать изменение цены подписки
+// @Description  Новая цена действует с effective_date (не раньше следующего месяца) и учитывается в прогнозе. Когда месяц наступает, цена подписки меняется и публикуется событие subscription.price_changed. Повторное изменение на тот же месяц заменяет прежнее.
+// @Tags         subscription
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID подписки (UUID)"
+// @Param        request body models.SchedulePriceChangeRequest true "Новая цена"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      201 {object} models.PriceChange
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /subscriptions/{id}/price-changes [post]
+func SchedulePriceChangeHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		subID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid subscription ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+			return
+		}
+
+		var req models.SchedulePriceChangeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Warnf("Bad request when scheduling a price change: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		change, err := services.SchedulePriceChange(db.WithContext(c.Request.Context()), tenant, subID, req)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		case err != nil:
+			log.Warnf("Failed to schedule price change: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Infof("Price change for subscription %s scheduled from %s", subID, change.EffectiveFrom)
+			c.JSON(http.StatusCreated, change)
+		}
+	}
+}
+
+// GetPriceChanges
+// @Summary      Изменения цены подписки
+// @Description  Запланированные и уже применённые (с applied_at) изменения.
+// @Tags         subscription
+// @Produce      json
+// @Param        id path string true "ID подписки (UUID)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {array} models.PriceChange
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /subscriptions/{id}/price-changes [get]
+func GetPriceChangesHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		subID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid subscription ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		changes, err := services.GetPriceChanges(db.WithContext(c.Request.Context()), tenant, subID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+			return
+		}
+		if err != nil {
+			log.Errorf("Error getting price changes: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch price changes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, changes)
+	}
+}
+
+// DeletePriceChange
+// @Summary      Отменить запланированное изменение цены
+// @Tags         subscription
+// @Produce      json
+// @Param        id path string true "ID подписки (UUID)"
+// @Param        change_id path string true "ID изменения (UUID)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string "Подписка не найдена или изменение уже применено"
+// @Failure      500 {object} map[string]string
+// @Router       /subscriptions/{id}/price-changes/{change_id} [delete]
+func DeletePriceChangeHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		subID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid subscription ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+			return
+		}
+		changeID, err := uuid.Parse(c.Param("change_id"))
+		if err != nil {
+			log.Warnf("Invalid price change ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid price change ID"})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		if err := services.DeletePriceChange(db.WithContext(c.Request.Context()), tenant, subID, changeID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "pending price change not found"})
+			} else {
+				log.Errorf("Failed to delete price change: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete price change"})
+			}
+			return
+		}
+
+		log.Infof("Price change %s cancelled", changeID)
+		c.JSON(http.StatusOK, gin.H{"message": "price change cancelled"})
+	}
+}
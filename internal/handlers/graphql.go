@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"subscribers/internal/graphqlapi"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQL
+// @Summary      GraphQL endpoint
+// @Description  Подписки пользователей, расходы по месяцам и по сервисам за один запрос. Запросы со сложностью или глубиной выше лимита отклоняются.
+// @Tags         graphql
+// @Accept       json
+// @Produce      json
+// @Param        request body graphqlapi.Request true "GraphQL запрос"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]string
+// @Router       /graphql [post]
+func GraphQLHandler(executor *graphqlapi.Executor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		var request graphqlapi.Request
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Warnf("Bad GraphQL request: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		result := executor.Execute(c.Request.Context(), request)
+		if result.HasErrors() {
+			log.Warnf("GraphQL request finished with errors: %v", result.Errors)
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
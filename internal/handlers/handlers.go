@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"subscribers/internal/models"
 	"subscribers/internal/services"
 	"subscribers/internal/utils"
@@ -20,38 +23,47 @@ import (
 // @Accept       json
 // @Produce      json
 // @Param        request body models.CreateSubscriptionRequest true "Данные для создания подписки"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
 // @Success      201 {object} map[string]string "Подписка успешно создана"
 // @Failure      400 {object} map[string]string "Неверные данные запроса"
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
 // @Failure      409 {object} map[string]string "Подписка уже существует"
+// @Failure      413 {object} map[string]string "Тело запроса больше MAX_BODY_BYTES"
 // @Failure      500 {object} map[string]string "Внутренняя ошибка сервера"
 // @Router       /createSubscription [post]
 func CreateSubscriptionHandler(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger.SugaredLogger.Info("Subscription addition started")
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Subscription addition started")
 
 		var request models.CreateSubscriptionRequest
 
-		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			logger.SugaredLogger.Warnf("Bad request when creating a book: " + err.Error())
+		if err := bindRequest(c, &request); err != nil {
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			log.Warnf("Bad request when creating a subscription: %v", err)
 			return
 		} else {
-			logger.SugaredLogger.Info("Successfully decoded request")
+			log.Debug("Successfully decoded request")
 		}
 
-		subId, err := services.CreateSubscription(db, request)
+		tenant := services.TenantFrom(c.Request.Context())
+		subId, err := services.CreateSubscription(db.WithContext(c.Request.Context()), tenant, request)
 		if err != nil {
-			logger.SugaredLogger.Warnf("Failed to create subscription: %v", err)
+			log.Warnf("Failed to create subscription: %v", err)
+			if accessDenied(c, err) {
+				return
+			}
 
 			status := http.StatusBadRequest
-			if err.Error() == "A subscription already exists for this user and the service." {
+			if errors.Is(err, services.ErrSubscriptionExists) {
 				status = http.StatusConflict
 			}
 			c.JSON(status, gin.H{"error": err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusCreated, gin.H{"message": "Подписка успешно создана", "id подписки": subId})
+		c.JSON(http.StatusCreated, gin.H{"message": "Подписка успешно создана", "id подписки": subId, "id": subId})
 	}
 }
 
@@ -61,36 +73,58 @@ func CreateSubscriptionHandler(db *gorm.DB) gin.HandlerFunc {
 // @Accept       json
 // @Produce      json
 // @Param        user_id query string true "ID пользователя (UUID)"
+// @Param        limit query int false "Размер страницы (по умолчанию — все подписки)"
+// @Param        offset query int false "Смещение от начала списка"
+// @Param        category query string false "Категория (фильтр)" Enums(entertainment, software, utilities, education, other)
+// @Param        tag query []string false "Тег (фильтр); можно повторить — подписка должна иметь все теги" collectionFormat(multi)
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
 // @Success      200 {array} models.SubscriptionSwagger
 // @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
 // @Failure      500 {object} map[string]string
 // @Router       /subscriptions [get]
 func GetSubscriptionsHandler(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger.SugaredLogger.Info("Get subscriptions started")
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Get subscriptions started")
 		userIDStr := c.Query("user_id")
 		if userIDStr == "" {
-			logger.SugaredLogger.Warn("Bad request when getting subscriptions, absent user_id")
+			log.Warn("Bad request when getting subscriptions, absent user_id")
 			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 			return
 		}
 
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			logger.SugaredLogger.Warn("Bad request when getting subscriptions, invalid UUID")
+			log.Warn("Bad request when getting subscriptions, invalid UUID")
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID format"})
 			return
 		}
 
-		subscriptions, err := services.GetSubscriptions(db, userID)
+		page, err := parsePagination(c)
+		if err != nil {
+			log.Warnf("Bad request when getting subscriptions: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		filter := models.SubscriptionFilter{Category: c.Query("category"), Tags: c.QueryArray("tag")}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		subscriptions, err := services.GetSubscriptions(db.WithContext(c.Request.Context()), tenant, userID, filter, page)
+		if accessDenied(c, err) {
+			log.Warnf("Get subscriptions denied: %v", err)
+			return
+		}
 		if err != nil {
-			logger.SugaredLogger.Errorf("Error getting subscriptions: %v", err)
+			log.Errorf("Error getting subscriptions: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch subsriptions"})
 			return
 		}
 
 		c.JSON(http.StatusOK, subscriptions)
-		logger.SugaredLogger.Info("Get subscriptions success")
+		log.Info("Get subscriptions success")
 	}
 }
 
@@ -99,35 +133,40 @@ func GetSubscriptionsHandler(db *gorm.DB) gin.HandlerFunc {
 // @Tags subscription
 // @Produce json
 // @Param id path string true "ID подписки (UUID)"
+// @Param X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
 // @Success 200 {object} models.SubscriptionSwagger
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Нет доступа в организации"
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /subscriptions/{id} [get]
 func GetSubscriptionHandler(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger.SugaredLogger.Info("Get one subscription by id started")
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Get one subscription by id started")
 		idStr := c.Param("id")
 		subID, err := uuid.Parse(idStr)
 		if err != nil {
-			logger.SugaredLogger.Warnf("Invalid subscription UUID: %v", err)
+			log.Warnf("Invalid subscription UUID: %v", err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
 			return
 		}
 
-		sub, err := services.GetSubscriptionByID(db, subID)
+		tenant := services.TenantFrom(c.Request.Context())
+		sub, err := services.GetSubscriptionByID(db.WithContext(c.Request.Context()), tenant, subID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				logger.SugaredLogger.Warnf("Get one subscription by id failed: %v", err)
+				log.Warnf("Get one subscription by id failed: %v", err)
 				c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			} else {
-				logger.SugaredLogger.Errorf("Error fetching subscription: %v", err)
+				log.Errorf("Error fetching subscription: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch subscription"})
 			}
 			return
 		}
 
-		logger.SugaredLogger.Info("Get one subscription by id success")
+		log.Info("Get one subscription by id success")
 		c.JSON(http.StatusOK, sub)
 	}
 }
@@ -140,42 +179,56 @@ func GetSubscriptionHandler(db *gorm.DB) gin.HandlerFunc {
 // @Produce json
 // @Param id path string true "ID подписки (UUID)"
 // @Param subscription body models.UpdateSubscriptionRequest true "Данные для обновления"
+// @Param X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Нет доступа в организации"
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "Подписка на этот сервис уже есть"
 // @Failure 500 {object} map[string]string
 // @Router /subscriptions/{id} [patch]
 func UpdateSubscriptionHandler(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger.SugaredLogger.Info("Update subscription started")
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Update subscription started")
 
 		idStr := c.Param("id")
 		subID, err := uuid.Parse(idStr)
 		if err != nil {
-			logger.SugaredLogger.Warnf("Invalid subscription ID: %v", err)
+			log.Warnf("Invalid subscription ID: %v", err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
 			return
 		}
 
 		var req models.UpdateSubscriptionRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			logger.SugaredLogger.Warnf("Invalid request body: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		if err := bindRequest(c, &req); err != nil {
+			log.Warnf("Invalid request body: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": "invalid request body"})
 			return
 		}
 
-		if err := services.UpdateSubscription(db, subID, req); err != nil {
+		tenant := services.TenantFrom(c.Request.Context())
+		if err := services.UpdateSubscription(db.WithContext(c.Request.Context()), tenant, subID, req); err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				logger.SugaredLogger.Warnf("Subscription not found: %v", err)
+				log.Warnf("Subscription not found: %v", err)
 				c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+			} else if errors.Is(err, services.ErrInvalidSplit) || errors.Is(err, services.ErrInvalidDate) {
+				log.Warnf("Invalid subscription update: %v", err)
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			} else if errors.Is(err, services.ErrSubscriptionExists) {
+				log.Warnf("Update subscription conflicts with another one: %v", err)
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			} else if accessDenied(c, err) {
+				log.Warnf("Update subscription denied: %v", err)
 			} else {
-				logger.SugaredLogger.Errorf("error UpdateSubscription: %v", err)
+				log.Errorf("error UpdateSubscription: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			}
 			return
 		}
 
-		logger.SugaredLogger.Info("Update subscription success")
+		log.Info("Update subscription success")
 		c.JSON(http.StatusOK, gin.H{"message": "subscription updated successfully"})
 	}
 }
@@ -186,67 +239,81 @@ func UpdateSubscriptionHandler(db *gorm.DB) gin.HandlerFunc {
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "ID подписки (UUID)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
 // @Success      200 {object} map[string]string
 // @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
 // @Failure      404 {object} map[string]string
 // @Failure      500 {object} map[string]string
 // @Router       /subscriptions/{id} [delete]
 func DeleteSubscriptionHandler(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger.SugaredLogger.Info("Delete subscription started")
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Delete subscription started")
 
 		idStr := c.Param("id")
 		subID, err := uuid.Parse(idStr)
 		if err != nil {
-			logger.SugaredLogger.Warnf("Invalid subscription ID: %v", err)
+			log.Warnf("Invalid subscription ID: %v", err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
 			return
 		}
 
-		err = services.DeleteSubscription(db, subID)
+		tenant := services.TenantFrom(c.Request.Context())
+		err = services.DeleteSubscription(db.WithContext(c.Request.Context()), tenant, subID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				logger.SugaredLogger.Warnf("subscription not found: %v", err)
+				log.Warnf("subscription not found: %v", err)
 				c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			} else {
-				logger.SugaredLogger.Errorf("Failed to delete subscription: %v", err)
+				log.Errorf("Failed to delete subscription: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete subscription"})
 			}
 			return
 		}
 
-		logger.SugaredLogger.Info("Delete subscription success")
+		log.Info("Delete subscription success")
 		c.JSON(http.StatusOK, gin.H{"message": "subscription deleted successfully"})
 	}
 }
 
 // GetSubscriptionsTotal
 // @Summary      Получить суммарную стоимость подписок за период с фильтрацией
+// @Description  По разделённым подпискам учитывается только доля пользователя, в том числе по подпискам, которые оплачивает другой участник. С group_by ответ дополняется суммами по группам; подписка с несколькими тегами входит в каждую их группу, пустой key — подписки без категории или без тегов.
 // @Tags         subscription
 // @Accept       json
 // @Produce      json
 // @Param        user_id query string true "ID пользователя (UUID)"
 // @Param        service_name query string false "Название подписки (фильтр)"
+// @Param        category query string false "Категория (фильтр)" Enums(entertainment, software, utilities, education, other)
+// @Param        tag query []string false "Тег (фильтр); можно повторить — подписка должна иметь все теги" collectionFormat(multi)
+// @Param        group_by query string false "Группировка" Enums(service, category, tag)
 // @Param        start_date query string false "Начало периода (формат: 01-2006)"
 // @Param        end_date query string false "Конец периода (формат: 01-2006)"
-// @Success      200 {object} map[string]int "Суммарная стоимость"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} models.SpendingBreakdown "Суммарная стоимость"
 // @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
+// @Failure      429 {object} map[string]string "Превышен лимит запросов"
 // @Failure      500 {object} map[string]string
 // @Router       /subscriptions/total [get]
 func GetSubscriptionsTotalHandler(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger.SugaredLogger.Info("Get total subscription started")
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Get total subscription started")
 
 		userIDStr := c.Query("user_id")
 		if userIDStr == "" {
-			logger.SugaredLogger.Warnf("user id is empty")
+			log.Warnf("user id is empty")
 			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 			return
 		}
 
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			logger.SugaredLogger.Warnf("invalid UUID format: %v", err)
+			log.Warnf("invalid UUID format: %v", err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID format"})
 			return
 		}
@@ -259,7 +326,7 @@ func GetSubscriptionsTotalHandler(db *gorm.DB) gin.HandlerFunc {
 		if startDateStr != "" {
 			ym, err := utils.ParseYearMonth(startDateStr)
 			if err != nil {
-				logger.SugaredLogger.Warnf("invalid start_date format: %v", err)
+				log.Warnf("invalid start_date format: %v", err)
 				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format"})
 				return
 			}
@@ -270,20 +337,92 @@ func GetSubscriptionsTotalHandler(db *gorm.DB) gin.HandlerFunc {
 		if endDateStr != "" {
 			ym, err := utils.ParseYearMonth(endDateStr)
 			if err != nil {
-				logger.SugaredLogger.Warnf("invalid end_date format: %v", err)
+				log.Warnf("invalid end_date format: %v", err)
 				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format"})
 				return
 			}
 			endYM = &ym
 		}
 
-		total, err := services.CalculateSubscriptionsTotal(db, userID, serviceName, startYM, endYM)
+		groupBy := c.Query("group_by")
+		switch groupBy {
+		case "", models.GroupByService, models.GroupByCategory, models.GroupByTag:
+		default:
+			log.Warnf("invalid group_by: %q", groupBy)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be service, category or tag"})
+			return
+		}
+
+		filter := models.SubscriptionFilter{
+			ServiceName: serviceName,
+			Category:    c.Query("category"),
+			Tags:        c.QueryArray("tag"),
+			StartYM:     startYM,
+			EndYM:       endYM,
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		breakdown, err := services.CalculateSpendingBreakdown(db.WithContext(c.Request.Context()), tenant, userID, filter, groupBy)
+		if accessDenied(c, err) {
+			log.Warnf("Get total subscription denied: %v", err)
+			return
+		}
 		if err != nil {
-			logger.SugaredLogger.Errorf("Failed to calculate total: %v", err)
+			log.Errorf("Failed to calculate total: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to calculate total"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"total_price": total})
+		c.JSON(http.StatusOK, breakdown)
+	}
+}
+
+func parsePagination(c *gin.Context) (models.Pagination, error) {
+	var page models.Pagination
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			return page, fmt.Errorf("invalid limit")
+		}
+		page.Limit = limit
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("invalid offset")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// bindRequest разбирает JSON тело и проверяет его методом Validate запроса;
+// тот же метод вызывает gRPC сервер.
+func bindRequest(c *gin.Context, request interface{ Validate() error }) error {
+	if err := json.NewDecoder(c.Request.Body).Decode(request); err != nil {
+		return err
+	}
+	return request.Validate()
+}
+
+// bindStatus выбирает код ответа для ошибки разбора тела: 413, если тело
+// больше лимита BodyLimit, иначе 400.
+func bindStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// accessDenied отвечает 403, если сервис отказал арендатору в доступе.
+func accessDenied(c *gin.Context, err error) bool {
+	if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrNotMember) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return true
 	}
+	return false
 }
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"subscribers/internal/health"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz
+// @Summary      Liveness probe
+// @Description  Процесс запущен и обрабатывает запросы. Зависимости не проверяются.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} map[string]string
+// @Router       /healthz [get]
+func HealthzHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+	}
+}
+
+// Readyz
+// @Summary      Readiness probe
+// @Description  Проверяет базу данных, применённые миграции и фоновые воркеры. Возвращает 503, если хотя бы одна проверка не прошла.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} health.Report
+// @Failure      503 {object} health.Report
+// @Router       /readyz [get]
+func ReadyzHandler(checker *health.Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		report := checker.Check(c.Request.Context())
+
+		status := http.StatusOK
+		if report.Status != health.StatusOK {
+			status = http.StatusServiceUnavailable
+			log.Warnf("Readiness check failed: %+v", report.Checks)
+		}
+		c.JSON(status, report)
+	}
+}
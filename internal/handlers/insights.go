@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetInsights
+// @Summary      Находки анализа подписок
+// @Description  Повышения цены (price_increase), подписки на один сервис каталога под разными названиями (duplicate), подписки без изменений больше двух лет (forgotten) и месяцы, когда расходы выросли в полтора раза против среднего за три предыдущих (spending_spike). Подписки анализируются в фоне раз в INSIGHTS_INTERVAL.
+// @Tags         insight
+// @Produce      json
+// @Param        user_id query string true "ID пользователя (UUID)"
+// @Param        kind query string false "Вид находок" Enums(price_increase, duplicate, forgotten, spending_spike)
+// @Param        include_dismissed query bool false "Вернуть и скрытые находки"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {array} models.Insight
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
+// @Failure      500 {object} map[string]string
+// @Router       /insights [get]
+func GetInsightsHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		userID, err := uuid.Parse(c.Query("user_id"))
+		if err != nil {
+			log.Warnf("Invalid user_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required and must be a UUID"})
+			return
+		}
+
+		kind := c.Query("kind")
+		if kind != "" && !models.IsInsightKind(kind) {
+			log.Warnf("Invalid insight kind: %q", kind)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown insight kind"})
+			return
+		}
+
+		includeDismissed := false
+		if s := c.Query("include_dismissed"); s != "" {
+			includeDismissed, err = strconv.ParseBool(s)
+			if err != nil {
+				log.Warnf("Invalid include_dismissed: %q", s)
+				c.JSON(http.StatusBadRequest, gin.H{"error": "include_dismissed must be a boolean"})
+				return
+			}
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		insights, err := services.GetInsights(db.WithContext(c.Request.Context()), tenant, userID, kind, includeDismissed)
+		if accessDenied(c, err) {
+			log.Warnf("Get insights denied: %v", err)
+			return
+		}
+		if err != nil {
+			log.Errorf("Error getting insights: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch insights"})
+			return
+		}
+
+		c.JSON(http.StatusOK, insights)
+	}
+}
+
+// DismissInsight
+// @Summary      Скрыть находку
+// @Description  Скрытая находка не возвращается по умолчанию и не создаётся анализом повторно.
+// @Tags         insight
+// @Produce      json
+// @Param        id path string true "ID находки (UUID)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /insights/{id}/dismiss [post]
+func DismissInsightHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid insight ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid insight ID"})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		if err := services.DismissInsight(db.WithContext(c.Request.Context()), tenant, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "insight not found"})
+			} else {
+				log.Errorf("Failed to dismiss insight: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to dismiss insight"})
+			}
+			return
+		}
+
+		log.Infof("Insight %s dismissed", id)
+		c.JSON(http.StatusOK, gin.H{"message": "insight dismissed"})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"subscribers/internal/models"
+	"subscribers/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLogLevel
+// @Summary      Текущий уровень логирования
+// @Description  Глобальный уровень и уровни компонентов, заданные отдельно.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Success      200 {object} models.LogLevelResponse
+// @Failure      401 {object} map[string]string
+// @Router       /admin/log-level [get]
+func GetLogLevelHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		level, components := logger.Levels()
+		c.JSON(http.StatusOK, models.LogLevelResponse{Level: level, Components: components})
+	}
+}
+
+// SetLogLevel
+// @Summary      Изменить уровень логирования
+// @Description  Меняет глобальный уровень или уровень компонента (handlers, services, db) без перезапуска. С revert_after прежний уровень вернётся автоматически.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request body models.LogLevelRequest true "Новый уровень"
+// @Success      200 {object} models.LogLevelResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Router       /admin/log-level [put]
+func SetLogLevelHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		var request models.LogLevelRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if request.Component == "" && request.Level == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "level is required"})
+			return
+		}
+
+		var revertAfter time.Duration
+		if request.RevertAfter != "" {
+			d, err := time.ParseDuration(request.RevertAfter)
+			if err != nil || d <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revert_after"})
+				return
+			}
+			revertAfter = d
+		}
+
+		if err := logger.SetLevel(request.Component, request.Level, revertAfter); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		log.Warnw("Log level changed",
+			"target_component", request.Component,
+			"level", request.Level,
+			"revert_after", request.RevertAfter,
+		)
+		level, components := logger.Levels()
+		c.JSON(http.StatusOK, models.LogLevelResponse{Level: level, Components: components})
+	}
+}
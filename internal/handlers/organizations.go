@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"subscribers/internal/middleware"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/internal/utils"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateOrganization
+// @Summary      Создать организацию
+// @Description  Пользователь из заголовка X-User-ID становится администратором организации. X-User-ID принимается только от сервисов из USER_IDENTITIES.
+// @Tags         organization
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID header string true "ID пользователя (UUID; только от сервисов из USER_IDENTITIES)"
+// @Param        request body models.CreateOrganizationRequest true "Данные организации"
+// @Success      201 {object} models.Organization
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /organizations [post]
+func CreateOrganizationHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		ownerID, ok := middleware.User(c)
+		if !ok {
+			log.Warn("Unauthorized request when creating an organization, no X-User-ID")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header with user UUID is required"})
+			return
+		}
+
+		var request models.CreateOrganizationRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Warnf("Bad request when creating an organization: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		org, err := services.CreateOrganization(db.WithContext(c.Request.Context()), ownerID, request)
+		if err != nil {
+			log.Errorf("Failed to create organization: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create organization"})
+			return
+		}
+
+		log.Info("Create organization success")
+		c.JSON(http.StatusCreated, org)
+	}
+}
+
+// GetMembers
+// @Summary      Получить участников организации
+// @Tags         organization
+// @Produce      json
+// @Param        org_id path string true "ID организации (UUID)"
+// @Param        X-User-ID header string true "ID пользователя (UUID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {array} models.Membership
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /organizations/{org_id}/members [get]
+func GetMembersHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		tenant := services.TenantFrom(c.Request.Context())
+		members, err := services.GetMembers(db.WithContext(c.Request.Context()), tenant)
+		if accessDenied(c, err) {
+			return
+		}
+		if err != nil {
+			log.Errorf("Error getting organization members: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch members"})
+			return
+		}
+
+		c.JSON(http.StatusOK, members)
+	}
+}
+
+// SetMember
+// @Summary      Добавить участника или изменить его роль
+// @Description  Доступно администраторам организации. Последний администратор не может понизить себя.
+// @Tags         organization
+// @Accept       json
+// @Produce      json
+// @Param        org_id path string true "ID организации (UUID)"
+// @Param        user_id path string true "ID участника (UUID)"
+// @Param        X-User-ID header string true "ID администратора (UUID; только от сервисов из USER_IDENTITIES)"
+// @Param        request body models.SetMemberRequest true "Роль участника"
+// @Success      200 {object} models.Membership
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /organizations/{org_id}/members/{user_id} [put]
+func SetMemberHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		userID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID format"})
+			return
+		}
+
+		var request models.SetMemberRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Warnf("Bad request when setting a member: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		member, err := services.SetMember(db.WithContext(c.Request.Context()), tenant, userID, request)
+		if accessDenied(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrLastAdmin) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Errorf("Failed to set organization member: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set member"})
+			return
+		}
+
+		log.Infof("Member %s now has role %s", userID, member.Role)
+		c.JSON(http.StatusOK, member)
+	}
+}
+
+// RemoveMember
+// @Summary      Исключить участника из организации
+// @Description  Подписки участника остаются в организации.
+// @Tags         organization
+// @Produce      json
+// @Param        org_id path string true "ID организации (UUID)"
+// @Param        user_id path string true "ID участника (UUID)"
+// @Param        X-User-ID header string true "ID администратора (UUID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /organizations/{org_id}/members/{user_id} [delete]
+func RemoveMemberHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		userID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID format"})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		err = services.RemoveMember(db.WithContext(c.Request.Context()), tenant, userID)
+		switch {
+		case accessDenied(c, err):
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		case errors.Is(err, services.ErrLastAdmin):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err != nil:
+			log.Errorf("Failed to remove organization member: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove member"})
+		default:
+			log.Infof("Member %s removed", userID)
+			c.JSON(http.StatusOK, gin.H{"message": "member removed successfully"})
+		}
+	}
+}
+
+// GetOrganizationTotals
+// @Summary      Суммы подписок организации по участникам и сервисам
+// @Description  Доступно администраторам. Период фильтруется по месяцу начала подписки, как в /subscriptions/total.
+// @Tags         organization
+// @Produce      json
+// @Param        org_id path string true "ID организации (UUID)"
+// @Param        X-User-ID header string true "ID администратора (UUID; только от сервисов из USER_IDENTITIES)"
+// @Param        start_date query string false "Начало периода (формат: 01-2006)"
+// @Param        end_date query string false "Конец периода (формат: 01-2006)"
+// @Success      200 {object} models.OrganizationTotals
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /organizations/{org_id}/totals [get]
+func GetOrganizationTotalsHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		startYM, endYM, err := parsePeriod(c)
+		if err != nil {
+			log.Warnf("Bad request when getting organization totals: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		totals, err := services.CalculateOrganizationTotals(db.WithContext(c.Request.Context()), tenant, startYM, endYM)
+		if accessDenied(c, err) {
+			return
+		}
+		if err != nil {
+			log.Errorf("Failed to calculate organization totals: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to calculate totals"})
+			return
+		}
+
+		c.JSON(http.StatusOK, totals)
+	}
+}
+
+// parsePeriod читает необязательные start_date и end_date.
+func parsePeriod(c *gin.Context) (startYM, endYM *models.YearMonth, err error) {
+	if s := c.Query("start_date"); s != "" {
+		ym, err := utils.ParseYearMonth(s)
+		if err != nil {
+			return nil, nil, errors.New("invalid start_date format")
+		}
+		startYM = &ym
+	}
+	if s := c.Query("end_date"); s != "" {
+		ym, err := utils.ParseYearMonth(s)
+		if err != nil {
+			return nil, nil, errors.New("invalid end_date format")
+		}
+		endYM = &ym
+	}
+	return startYM, endYM, nil
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SetSubscriptionMembers
+// @Summary      Разделить подписку между пользователями
+// @Description  Заменяет участников подписки. Плательщик — владелец подписки, ему достаётся остаток после долей участников. Правила: equal — поровну, percent — проценты участников (в сумме не больше 100), fixed — фиксированные суммы (в сумме не больше стоимости). Пустой список участников отменяет разделение.
+// @Tags         subscription
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID подписки (UUID)"
+// @Param        request body models.SetMembersRequest true "Правило и участники"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} models.SubscriptionSwagger
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /subscriptions/{id}/members [put]
+func SetSubscriptionMembersHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Set subscription members started")
+
+		subID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid subscription ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+			return
+		}
+
+		var req models.SetMembersRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Warnf("Bad request when setting subscription members: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		sub, err := services.SetSubscriptionMembers(db.WithContext(c.Request.Context()), tenant, subID, req)
+		switch {
+		case accessDenied(c, err):
+			log.Warnf("Set subscription members denied: %v", err)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		case errors.Is(err, services.ErrInvalidSplit):
+			log.Warnf("Invalid cost split: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err != nil:
+			log.Errorf("Failed to set subscription members: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set subscription members"})
+		default:
+			log.Infof("Subscription %s split between %d members", subID, len(sub.Members))
+			c.JSON(http.StatusOK, sub)
+		}
+	}
+}
+
+// GetSettlements
+// @Summary      Взаиморасчёты по разделённым подпискам
+// @Description  Для каждого месяца периода — кто кому сколько должен с участием пользователя, после взаимозачёта. Без start_date период начинается с самой ранней подписки, без end_date — заканчивается текущим месяцем; не больше 120 месяцев.
+// @Tags         subscription
+// @Produce      json
+// @Param        user_id query string true "ID пользователя (UUID)"
+// @Param        start_date query string false "Начало периода (формат: 01-2006)"
+// @Param        end_date query string false "Конец периода (формат: 01-2006)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {array} models.MonthlySettlement
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Нет доступа в организации"
+// @Failure      500 {object} map[string]string
+// @Router       /subscriptions/settlements [get]
+func GetSettlementsHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+
+		userID, err := uuid.Parse(c.Query("user_id"))
+		if err != nil {
+			log.Warnf("Invalid user_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required and must be a UUID"})
+			return
+		}
+
+		startYM, endYM, err := parsePeriod(c)
+		if err != nil {
+			log.Warnf("Bad request when getting settlements: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		settlements, err := services.GetSettlements(db.WithContext(c.Request.Context()), tenant, userID, startYM, endYM)
+		if accessDenied(c, err) {
+			log.Warnf("Get settlements denied: %v", err)
+			return
+		}
+		if errors.Is(err, services.ErrPeriodTooLong) {
+			log.Warnf("Bad request when getting settlements: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Errorf("Failed to calculate settlements: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to calculate settlements"})
+			return
+		}
+
+		c.JSON(http.StatusOK, settlements)
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateWebhook
+// @Summary      Зарегистрировать webhook endpoint
+// @Description  Endpoint получает события пространства, в котором зарегистрирован: организации из X-Organization-ID или личного. События подписываются HMAC-SHA256 секретом и передаются в заголовке X-Webhook-Signature (формат sha256=<hex>).
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        request body models.CreateWebhookRequest true "Данные endpoint'а"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      201 {object} models.WebhookEndpoint
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Webhook'ами организации управляют только администраторы"
+// @Failure      500 {object} map[string]string
+// @Router       /webhooks [post]
+func CreateWebhookHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		log.Debug("Webhook registration started")
+
+		var request models.CreateWebhookRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Warnf("Bad request when registering a webhook: %v", err)
+			c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		endpoint, err := services.CreateWebhook(db.WithContext(c.Request.Context()), tenant, request)
+		if accessDenied(c, err) {
+			return
+		}
+		if err != nil {
+			log.Warnf("Failed to register webhook: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		log.Info("Webhook registration success")
+		c.JSON(http.StatusCreated, endpoint)
+	}
+}
+
+// GetWebhooks
+// @Summary      Получить список webhook endpoint'ов
+// @Tags         webhook
+// @Produce      json
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {array} models.WebhookEndpoint
+// @Failure      403 {object} map[string]string "Webhook'ами организации управляют только администраторы"
+// @Failure      500 {object} map[string]string
+// @Router       /webhooks [get]
+func GetWebhooksHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		tenant := services.TenantFrom(c.Request.Context())
+		endpoints, err := services.GetWebhooks(db.WithContext(c.Request.Context()), tenant)
+		if accessDenied(c, err) {
+			return
+		}
+		if err != nil {
+			log.Errorf("Error getting webhooks: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch webhooks"})
+			return
+		}
+
+		c.JSON(http.StatusOK, endpoints)
+	}
+}
+
+// DeleteWebhook
+// @Summary      Удалить webhook endpoint
+// @Tags         webhook
+// @Produce      json
+// @Param        id path string true "ID endpoint'а (UUID)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Webhook'ами организации управляют только администраторы"
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /webhooks/{id} [delete]
+func DeleteWebhookHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid webhook ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		if err := services.DeleteWebhook(db.WithContext(c.Request.Context()), tenant, id); err != nil {
+			if accessDenied(c, err) {
+				return
+			}
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+			} else {
+				log.Errorf("Failed to delete webhook: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete webhook"})
+			}
+			return
+		}
+
+		log.Info("Delete webhook success")
+		c.JSON(http.StatusOK, gin.H{"message": "webhook deleted successfully"})
+	}
+}
+
+// GetFailedWebhookDeliveries
+// @Summary      Dead-letter очередь webhook'ов
+// @Description  Доставки, исчерпавшие все попытки отправки.
+// @Tags         webhook
+// @Produce      json
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      200 {array} models.WebhookDelivery
+// @Failure      403 {object} map[string]string "Webhook'ами организации управляют только администраторы"
+// @Failure      500 {object} map[string]string
+// @Router       /webhooks/deliveries/failed [get]
+func GetFailedWebhookDeliveriesHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		tenant := services.TenantFrom(c.Request.Context())
+		deliveries, err := services.GetFailedWebhookDeliveries(db.WithContext(c.Request.Context()), tenant)
+		if accessDenied(c, err) {
+			return
+		}
+		if err != nil {
+			log.Errorf("Error getting failed webhook deliveries: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch webhook deliveries"})
+			return
+		}
+
+		c.JSON(http.StatusOK, deliveries)
+	}
+}
+
+// RedeliverWebhook
+// @Summary      Повторно отправить webhook
+// @Description  Возвращает доставку в очередь со сброшенным счётчиком попыток.
+// @Tags         webhook
+// @Produce      json
+// @Param        id path string true "ID доставки (UUID)"
+// @Param        X-Organization-ID header string false "ID организации (UUID); без него — личное пространство"
+// @Param        X-User-ID header string false "ID пользователя, от имени которого выполняется запрос (обязателен с X-Organization-ID; только от сервисов из USER_IDENTITIES)"
+// @Success      202 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string "Webhook'ами организации управляют только администраторы"
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /webhooks/deliveries/{id}/redeliver [post]
+func RedeliverWebhookHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logger.FromContext(c.Request.Context())
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			log.Warnf("Invalid delivery ID: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery ID"})
+			return
+		}
+
+		tenant := services.TenantFrom(c.Request.Context())
+		if err := services.RedeliverWebhookDelivery(db.WithContext(c.Request.Context()), tenant, id); err != nil {
+			if accessDenied(c, err) {
+				return
+			}
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "delivery not found"})
+			} else {
+				log.Errorf("Failed to redeliver webhook: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to redeliver webhook"})
+			}
+			return
+		}
+
+		log.Infof("Webhook delivery %s queued for redelivery", id)
+		c.JSON(http.StatusAccepted, gin.H{"message": "delivery queued"})
+	}
+}
@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"subscribers/internal/db"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// Database проверяет соединение с базой через пул sql.DB.
+func Database(gormDB *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := gormDB.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// Migrations проверяет, что все таблицы и колонки моделей созданы.
+// Схема не откатывается сама по себе, поэтому успешный результат запоминается.
+func Migrations(gormDB *gorm.DB) CheckFunc {
+	var applied atomic.Bool
+	return func(ctx context.Context) error {
+		if applied.Load() {
+			return nil
+		}
+
+		statuses, err := db.MigrationStatus(gormDB.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		var pending []string
+		for _, s := range statuses {
+			if !s.Exists || len(s.MissingColumns) > 0 {
+				pending = append(pending, s.Table)
+			}
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("pending migrations for: %s", strings.Join(pending, ", "))
+		}
+
+		applied.Store(true)
+		return nil
+	}
+}
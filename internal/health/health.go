@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type CheckResult struct {
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Checker выполняет проверки готовности сервиса. Проверки запускаются
+// параллельно, каждая ограничена timeout.
+type Checker struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	names        []string
+	checks       map[string]CheckFunc
+	shuttingDown atomic.Bool
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Checker{timeout: timeout, checks: make(map[string]CheckFunc)}
+}
+
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.checks[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// SetShuttingDown переводит сервис в «не готов», чтобы балансировщик
+// перестал направлять трафик, пока идёт остановка.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	names := append([]string(nil), c.names...)
+	checks := make(map[string]CheckFunc, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(names)+1)}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			result := run(ctx, c.timeout, check)
+
+			mu.Lock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+			mu.Unlock()
+		}(name, checks[name])
+	}
+	wg.Wait()
+
+	if c.shuttingDown.Load() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = CheckResult{Status: StatusFail, Error: "service is shutting down"}
+	}
+	return report
+}
+
+func run(ctx context.Context, timeout time.Duration, check CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{Status: StatusOK, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Running — проверка фонового воркера.
+func Running(running func() bool) CheckFunc {
+	return func(ctx context.Context) error {
+		if !running() {
+			return errors.New("not running")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"subscribers/logger"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// businessCollector считает показатели подписок при каждом scrape,
+// поэтому значения всегда соответствуют базе, а не состоянию процесса.
+type businessCollector struct {
+	db      *gorm.DB
+	timeout time.Duration
+
+	activeSubscriptions *prometheus.Desc
+	monthlySpend        *prometheus.Desc
+	scrapeErrors        prometheus.Counter
+}
+
+func newBusinessCollector(db *gorm.DB) *businessCollector {
+	return &businessCollector{
+		db:      db,
+		timeout: 5 * time.Second,
+		activeSubscriptions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "active_subscriptions"),
+			"Subscriptions active in the current month by service.",
+			[]string{"service"}, nil,
+		),
+		monthlySpend: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "monthly_spend"),
+			"Total monthly price of active subscriptions by service.",
+			[]string{"service"}, nil,
+		),
+		scrapeErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "business_metrics_errors_total",
+			Help:      "Failed attempts to calculate subscription metrics.",
+		}),
+	}
+}
+
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.activeSubscriptions
+	ch <- c.monthlySpend
+	c.scrapeErrors.Describe(ch)
+}
+
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	defer c.scrapeErrors.Collect(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	activity, err := services.GetServiceActivity(c.db.WithContext(ctx), models.YearMonth{Year: now.Year(), Month: now.Month()})
+	if err != nil {
+		c.scrapeErrors.Inc()
+		logger.SugaredLogger.Warnf("Failed to collect subscription metrics: %v", err)
+		return
+	}
+
+	for _, service := range activity {
+		ch <- prometheus.MustNewConstMetric(c.activeSubscriptions, prometheus.GaugeValue, float64(service.Subscriptions), service.ServiceName)
+		ch <- prometheus.MustNewConstMetric(c.monthlySpend, prometheus.GaugeValue, float64(service.MonthlySpend), service.ServiceName)
+	}
+}
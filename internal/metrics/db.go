@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// RegisterDB подключает метрики базы: длительность запросов GORM,
+// статистику пула sql.DB и бизнес-показатели подписок.
+func RegisterDB(db *gorm.DB) error {
+	if err := db.Use(gormPlugin{}); err != nil {
+		return fmt.Errorf("failed to register GORM metrics: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := prometheus.Register(collectors.NewDBStatsCollector(sqlDB, "primary")); err != nil {
+		return fmt.Errorf("failed to register DB pool metrics: %w", err)
+	}
+	if err := prometheus.Register(newBusinessCollector(db)); err != nil {
+		return fmt.Errorf("failed to register subscription metrics: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+const startedAtKey = "metrics:started_at"
+
+var queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "db_query_duration_seconds",
+	Help:      "GORM statement duration by operation and table.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"operation", "table", "status"})
+
+// gormPlugin замеряет каждый запрос через колбэки GORM.
+type gormPlugin struct{}
+
+func (gormPlugin) Name() string {
+	return "subscribers:metrics"
+}
+
+func (gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("metrics:before_create", before),
+		cb.Create().After("*").Register("metrics:after_create", after("create")),
+		cb.Query().Before("*").Register("metrics:before_query", before),
+		cb.Query().After("*").Register("metrics:after_query", after("query")),
+		cb.Update().Before("*").Register("metrics:before_update", before),
+		cb.Update().After("*").Register("metrics:after_update", after("update")),
+		cb.Delete().Before("*").Register("metrics:before_delete", before),
+		cb.Delete().After("*").Register("metrics:after_delete", after("delete")),
+		cb.Row().Before("*").Register("metrics:before_row", before),
+		cb.Row().After("*").Register("metrics:after_row", after("row")),
+		cb.Raw().Before("*").Register("metrics:before_raw", before),
+		cb.Raw().After("*").Register("metrics:after_raw", after("raw")),
+	)
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(startedAtKey, time.Now())
+}
+
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startedAtKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		status := "ok"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			status = "error"
+		}
+		queryDuration.WithLabelValues(operation, table, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth пускает к административным маршрутам с заголовком
+// Authorization: Bearer <token> или с клиентским сертификатом одного из
+// сервисов identities. Если не настроено ни то, ни другое, маршруты недоступны.
+func AdminAuth(token string, identities []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" && len(identities) == 0 {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "admin API is disabled"})
+			return
+		}
+
+		if identity, ok := Identity(c); ok && slices.Contains(identities, identity) {
+			c.Next()
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+type CORSOptions struct {
+	// AllowedOrigins — список origin вида https://app.example.com; "*" разрешает любой.
+	// Пустой список отключает CORS.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// exposedHeaders — заголовки ответа, которые нужны браузерному клиенту.
+var exposedHeaders = []string{
+	RequestIDHeader,
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"RateLimit-Policy",
+	"Retry-After",
+}
+
+// CORS отвечает на preflight запросы и добавляет Access-Control-* заголовки
+// для разрешённых origin. Опции должны быть проверены заранее (Config.Validate).
+func CORS(opts CORSOptions) gin.HandlerFunc {
+	if len(opts.AllowedOrigins) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	config := cors.Config{
+		AllowMethods:     opts.AllowedMethods,
+		AllowHeaders:     opts.AllowedHeaders,
+		AllowCredentials: opts.AllowCredentials,
+		ExposeHeaders:    exposedHeaders,
+		MaxAge:           opts.MaxAge,
+	}
+	if slices.Contains(opts.AllowedOrigins, "*") {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = opts.AllowedOrigins
+	}
+	return cors.New(config)
+}
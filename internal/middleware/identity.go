@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"subscribers/internal/certs"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const identityKey = "client_identity"
+
+// ClientIdentity определяет сервис по проверенному клиентскому сертификату
+// (mTLS); сервис попадает в access log. Сертификат, субъект которого не
+// указан в identities, отклоняется с 403; запросы без сертификата проходят
+// дальше — их допускает режим TLS_CLIENT_AUTH=optional.
+func ClientIdentity(identities certs.Identities) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 || len(identities) == 0 {
+			c.Next()
+			return
+		}
+
+		cert := c.Request.TLS.VerifiedChains[0][0]
+		identity, ok := identities.Resolve(cert)
+		if !ok {
+			logger.FromContext(c.Request.Context()).Warnf("Client certificate %q is not mapped to a service identity", cert.Subject.String())
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "unknown client certificate"})
+			return
+		}
+
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// Identity возвращает сервис, определённый ClientIdentity.
+func Identity(c *gin.Context) (string, bool) {
+	identity := c.GetString(identityKey)
+	return identity, identity != ""
+}
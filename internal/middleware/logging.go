@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"runtime/debug"
+	"subscribers/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// служебные маршруты опрашиваются постоянно, их access log пишется на debug.
+var quietRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// RequestLogger присваивает запросу ID (или берёт его из X-Request-ID),
+// кладёт в контекст логгер с полями запроса и пишет access log.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		route := c.FullPath()
+		fields := []interface{}{
+			"request_id", requestID,
+			"method", c.Request.Method,
+			"route", route,
+		}
+		if userID := c.Query("user_id"); userID != "" {
+			fields = append(fields, "user_id", userID)
+		}
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			fields = append(fields, "trace_id", span.TraceID().String())
+		}
+
+		log := logger.Component(logger.ComponentHandlers).With(fields...)
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), log))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		access := []interface{}{
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+			"bytes", max(c.Writer.Size(), 0),
+		}
+		if identity, ok := Identity(c); ok {
+			access = append(access, "client_identity", identity)
+		}
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.Errorw("HTTP request", access...)
+		case quietRoutes[route]:
+			log.Debugw("HTTP request", access...)
+		default:
+			log.Infow("HTTP request", access...)
+		}
+	}
+}
+
+// Recovery отвечает 500 на панику в хендлере и пишет её в лог запроса.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logger.FromContext(c.Request.Context()).Errorw("Panic while handling request", "panic", recovered, "stack", string(debug.Stack()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	})
+}
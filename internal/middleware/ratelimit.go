@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"subscribers/internal/ratelimit"
+	"subscribers/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit ограничивает частоту запросов к группе маршрутов. Корзина
+// выбирается по пользователю, принятому ActingUser, затем по сервису из
+// клиентского сертификата, иначе по IP клиента; у каждой группы свои корзины.
+// Если хранилище недоступно, запрос пропускается.
+func RateLimit(store ratelimit.Store, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	if store == nil || !limit.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	policy := strconv.Itoa(limit.Burst) + ";w=" + strconv.Itoa(int(limit.Period.Seconds()))
+	return func(c *gin.Context) {
+		res, err := store.Take(c.Request.Context(), group+":"+rateLimitKey(c), limit)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Warnf("Rate limiter is unavailable, request allowed: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(res.Reset))
+
+		if !res.Allowed {
+			c.Header("Retry-After", ceilSeconds(res.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey определяет клиента. Ключом служит только то, что клиент не
+// может выбрать сам: пользователь от доверенного сервиса, сервис из
+// проверенного сертификата или IP (заголовки прокси учитываются только от
+// TRUSTED_PROXIES). Прочие заголовки и параметры запроса не подходят — с
+// новым значением в каждом запросе клиент получал бы новую полную корзину.
+func rateLimitKey(c *gin.Context) string {
+	if userID, ok := User(c); ok {
+		return "user:" + userID.String()
+	}
+	if identity, ok := Identity(c); ok {
+		return "svc:" + identity
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRateLimitKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		proxies    []string
+		identity   string
+		remoteAddr string
+		headers    map[string]string
+		wantStatus int
+		wantKey    string
+	}{
+		{
+			name:       "client IP",
+			remoteAddr: "203.0.113.7:1234",
+			wantStatus: http.StatusOK,
+			wantKey:    "ip:203.0.113.7",
+		},
+		{
+			name:       "forwarded header from an untrusted peer",
+			remoteAddr: "203.0.113.7:1234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			wantStatus: http.StatusOK,
+			wantKey:    "ip:203.0.113.7",
+		},
+		{
+			name:       "forwarded header from a trusted proxy",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:1234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			wantStatus: http.StatusOK,
+			wantKey:    "ip:198.51.100.1",
+		},
+		{
+			name:       "service",
+			identity:   "reports",
+			remoteAddr: "203.0.113.7:1234",
+			wantStatus: http.StatusOK,
+			wantKey:    "svc:reports",
+		},
+		{
+			name:       "user from a trusted service",
+			identity:   "billing",
+			remoteAddr: "203.0.113.7:1234",
+			headers:    map[string]string{UserHeader: userID.String()},
+			wantStatus: http.StatusOK,
+			wantKey:    "user:" + userID.String(),
+		},
+		{
+			name:       "user from an untrusted service",
+			identity:   "reports",
+			remoteAddr: "203.0.113.7:1234",
+			headers:    map[string]string{UserHeader: userID.String()},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "user without a certificate",
+			remoteAddr: "203.0.113.7:1234",
+			headers:    map[string]string{UserHeader: userID.String()},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if err := router.SetTrustedProxies(tt.proxies); err != nil {
+				t.Fatalf("SetTrustedProxies: %v", err)
+			}
+			var key string
+			router.GET("/",
+				func(c *gin.Context) {
+					if tt.identity != "" {
+						c.Set(identityKey, tt.identity)
+					}
+				},
+				ActingUser([]string{"billing"}),
+				func(c *gin.Context) { key = rateLimitKey(c) },
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if key != tt.wantKey {
+				t.Errorf("key = %q, want %q", key, tt.wantKey)
+			}
+		})
+	}
+}
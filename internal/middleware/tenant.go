@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"subscribers/internal/services"
+	"subscribers/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	OrganizationHeader = "X-Organization-ID"
+	UserHeader         = "X-User-ID"
+)
+
+const userKey = "acting_user"
+
+// ActingUser принимает пользователя, от имени которого выполняется запрос, из
+// X-User-ID (см. User). Сам сервис пользователей не проверяет, поэтому
+// заголовок принимается только от сервисов userIdentities, подтверждённых
+// клиентским сертификатом; от остальных запрос отклоняется с 401. Обращений к
+// базе нет, поэтому ActingUser работает до RateLimit, и лимит считается по
+// пользователю.
+func ActingUser(userIdentities []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(UserHeader)
+		if header == "" {
+			c.Next()
+			return
+		}
+		if identity, ok := Identity(c); !ok || !slices.Contains(userIdentities, identity) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": UserHeader + " is accepted only from a trusted client certificate"})
+			return
+		}
+		userID, err := uuid.Parse(header)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + UserHeader + " header"})
+			return
+		}
+		c.Set(userKey, userID)
+		c.Next()
+	}
+}
+
+// Tenant определяет, от имени какой организации выполняется запрос, и кладёт
+// арендатора в контекст запроса (services.TenantFrom). Организация берётся из
+// параметра пути :org_id или заголовка X-Organization-ID, пользователь — из
+// принятого ActingUser. Без организации запрос работает в личном пространстве.
+func Tenant(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := User(c)
+
+		orgStr := c.Param("org_id")
+		if orgStr == "" {
+			orgStr = c.GetHeader(OrganizationHeader)
+		}
+		if orgStr == "" {
+			c.Next()
+			return
+		}
+
+		orgID, err := uuid.Parse(orgStr)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid organization ID"})
+			return
+		}
+		if userID == uuid.Nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": UserHeader + " header with user UUID is required"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		tenant, err := services.ResolveTenant(db.WithContext(ctx), orgID, userID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "organization not found"})
+			return
+		case errors.Is(err, services.ErrNotMember):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			logger.FromContext(ctx).Errorf("Failed to resolve tenant: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve organization"})
+			return
+		}
+
+		log := logger.FromContext(ctx).With("org_id", orgID.String())
+		ctx = logger.WithContext(services.WithTenant(ctx, tenant), log)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// User возвращает пользователя, от имени которого выполняется запрос, если
+// ActingUser принял его из X-User-ID.
+func User(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get(userKey)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return userID.(uuid.UUID), true
+}
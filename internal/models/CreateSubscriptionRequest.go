@@ -1,14 +1,28 @@
 package models
 
+import "github.com/gin-gonic/gin/binding"
+
 // CreateSubscriptionRequest represents запрос на создание подписки
 // swagger:model CreateSubscriptionRequest
 type CreateSubscriptionRequest struct {
 	// Название сервиса
 	// required: true
 	ServiceName string `json:"service_name" binding:"required"`
-	// Цена подписки (мин 0)
-	// required: true
-	Price int `json:"price" binding:"required,min=0"`
+	// Цена подписки (мин 0); без неё берётся цена тарифа plan из каталога
+	// required: false
+	Price int `json:"price" binding:"required_without=Plan,min=0"`
+	// Период оплаты в месяцах: 1, 3, 6 или 12 (по умолчанию 1); price — цена за месяц
+	// required: false
+	BillingPeriod int `json:"billing_period,omitempty" binding:"omitempty,oneof=1 3 6 12"`
+	// Тариф сервиса из каталога
+	// required: false
+	Plan string `json:"plan,omitempty"`
+	// Категория: entertainment, software, utilities, education или other; без неё берётся категория из каталога
+	// required: false
+	Category string `json:"category,omitempty" binding:"omitempty,oneof=entertainment software utilities education other"`
+	// Произвольные теги
+	// required: false
+	Tags []string `json:"tags,omitempty"`
 	// ID пользователя в формате UUID
 	// required: true
 	UserID string `json:"user_id" binding:"required,uuid"`
@@ -19,3 +33,9 @@ type CreateSubscriptionRequest struct {
 	// required: false
 	EndDate *string `json:"end_date,omitempty"`
 }
+
+// Validate проверяет запрос по правилам из тегов binding. Его вызывают и REST
+// хендлер, и gRPC сервер, поэтому правила у обоих API одни.
+func (r *CreateSubscriptionRequest) Validate() error {
+	return binding.Validator.ValidateStruct(r)
+}
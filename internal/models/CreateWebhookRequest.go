@@ -0,0 +1,15 @@
+package models
+
+// CreateWebhookRequest — запрос на регистрацию webhook endpoint
+// swagger:model CreateWebhookRequest
+type CreateWebhookRequest struct {
+	// URL, на который отправляются события
+	// required: true
+	URL string `json:"url" binding:"required,url"`
+	// Секрет для HMAC-SHA256 подписи тела запроса
+	// required: true
+	Secret string `json:"secret" binding:"required,min=16"`
+	// Список событий (subscription.created, subscription.updated, subscription.deleted, subscription.ended, subscription.price_changed, budget.exceeded). Пустой — все события
+	// required: false
+	Events []string `json:"events,omitempty"`
+}
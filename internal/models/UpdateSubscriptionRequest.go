@@ -1,5 +1,7 @@
 package models
 
+import "github.com/gin-gonic/gin/binding"
+
 type UpdateSubscriptionRequest struct {
 	// Название сервиса
 	ServiceName *string `json:"service_name,omitempty"`
@@ -9,4 +11,15 @@ type UpdateSubscriptionRequest struct {
 	StartDate *string `json:"start_date,omitempty"`
 	// Дата окончания подписки (формат: 01-2006)
 	EndDate *string `json:"end_date,omitempty"`
+	// Период оплаты в месяцах: 1, 3, 6 или 12
+	BillingPeriod *int `json:"billing_period,omitempty" binding:"omitempty,oneof=1 3 6 12"`
+	// Категория; пустая строка убирает категорию
+	Category *string `json:"category,omitempty" binding:"omitempty,oneof=entertainment software utilities education other ''"`
+	// Теги; заменяют текущие, пустой список убирает все
+	Tags *[]string `json:"tags,omitempty"`
+}
+
+// Validate проверяет запрос по тем же правилам, что и CreateSubscriptionRequest.Validate.
+func (r *UpdateSubscriptionRequest) Validate() error {
+	return binding.Validator.ValidateStruct(r)
 }
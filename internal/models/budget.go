@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Области действия бюджета.
+const (
+	BudgetOverall  = "overall"
+	BudgetCategory = "category"
+	BudgetService  = "service"
+)
+
+// Budget — месячный лимит расходов пользователя на все подписки, одну
+// категорию или один сервис (Target — категория или каноническое название).
+// Бюджет относится к тому же пространству, что и подписки: личному или организации.
+type Budget struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index"`
+	OrgID     *uuid.UUID `json:"org_id,omitempty" gorm:"type:uuid;index"`
+	Scope     string     `json:"scope"`
+	Target    string     `json:"target,omitempty"`
+	Amount    int        `json:"amount"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Applies сообщает, учитывается ли подписка в этом бюджете.
+func (b Budget) Applies(sub Subscription) bool {
+	switch b.Scope {
+	case BudgetCategory:
+		return sub.Category == b.Target
+	case BudgetService:
+		return sub.ServiceName == b.Target
+	default:
+		return true
+	}
+}
+
+// BudgetRequest represents запрос на создание или изменение бюджета
+// swagger:model BudgetRequest
+type BudgetRequest struct {
+	// ID пользователя в формате UUID
+	// required: true
+	UserID string `json:"user_id" binding:"required,uuid"`
+	// Область: overall, category или service
+	// required: true
+	Scope string `json:"scope" binding:"required,oneof=overall category service"`
+	// Категория (для category) или название сервиса (для service)
+	Target string `json:"target,omitempty" binding:"required_unless=Scope overall"`
+	// Лимит в месяц
+	// required: true
+	Amount int `json:"amount" binding:"required,min=1"`
+}
+
+// UpdateBudgetRequest represents запрос на изменение лимита бюджета
+// swagger:model UpdateBudgetRequest
+type UpdateBudgetRequest struct {
+	// Лимит в месяц
+	// required: true
+	Amount int `json:"amount" binding:"required,min=1"`
+}
+
+// BudgetMonth — расходы в месяце против бюджета. Spent — сумма долей
+// пользователя в подписках, действующих в этом месяце.
+type BudgetMonth struct {
+	Month     YearMonth `json:"month" swaggertype:"string" example:"2024-01"`
+	Spent     int       `json:"spent"`
+	Remaining int       `json:"remaining"`
+	Over      bool      `json:"over"`
+}
+
+// BudgetStatus — бюджет и его исполнение по месяцам периода.
+type BudgetStatus struct {
+	Budget Budget        `json:"budget"`
+	Months []BudgetMonth `json:"months"`
+}
+
+// BudgetExceededData — данные события budget.exceeded: изменение подписки
+// SubscriptionID вывело бюджет за лимит в перечисленных месяцах.
+type BudgetExceededData struct {
+	Budget         Budget        `json:"budget"`
+	SubscriptionID uuid.UUID     `json:"subscription_id"`
+	Months         []BudgetMonth `json:"months"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CatalogService — запись каталога сервисов: каноническое название, по
+// которому считаются итоги, и варианты написания, которые к нему сводятся.
+type CatalogService struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	Name      string         `json:"name" gorm:"uniqueIndex"`
+	Category  string         `json:"category,omitempty" gorm:"index"`
+	Website   string         `json:"website,omitempty"`
+	Aliases   []ServiceAlias `json:"aliases" gorm:"foreignKey:ServiceID"`
+	Plans     []ServicePlan  `json:"plans" gorm:"foreignKey:ServiceID"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ServiceAlias — нормализованное написание названия (см. NormalizeServiceName).
+// Каноническое название тоже хранится как алиас, поэтому поиск идёт только
+// по этой таблице; алиас принадлежит одному сервису.
+type ServiceAlias struct {
+	Alias     string    `json:"alias" gorm:"primaryKey"`
+	ServiceID uuid.UUID `json:"-" gorm:"type:uuid;index"`
+}
+
+// ServicePlan — тариф сервиса с ценой по умолчанию.
+type ServicePlan struct {
+	ServiceID uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Name      string    `json:"name" gorm:"primaryKey"`
+	Price     int       `json:"price"`
+}
+
+// NormalizeServiceName приводит название к виду для сравнения: нижний
+// регистр, без лишних пробелов.
+func NormalizeServiceName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Plan возвращает тариф по названию без учёта регистра.
+func (s CatalogService) Plan(name string) (ServicePlan, bool) {
+	for _, plan := range s.Plans {
+		if strings.EqualFold(plan.Name, name) {
+			return plan, true
+		}
+	}
+	return ServicePlan{}, false
+}
+
+// CatalogServiceRequest represents запрос на создание или замену записи каталога
+// swagger:model CatalogServiceRequest
+type CatalogServiceRequest struct {
+	// Каноническое название
+	// required: true
+	Name string `json:"name" binding:"required"`
+	// Категория: entertainment, software, utilities, education или other
+	Category string `json:"category,omitempty" binding:"omitempty,oneof=entertainment software utilities education other"`
+	// Сайт сервиса
+	Website string `json:"website,omitempty" binding:"omitempty,url"`
+	// Другие написания названия; регистр и лишние пробелы не учитываются
+	Aliases []string `json:"aliases,omitempty"`
+	// Тарифы с ценами по умолчанию
+	Plans []ServicePlanRequest `json:"plans,omitempty" binding:"dive"`
+}
+
+type ServicePlanRequest struct {
+	// Название тарифа
+	// required: true
+	Name string `json:"name" binding:"required"`
+	// Цена в месяц
+	Price int `json:"price" binding:"min=0"`
+}
+
+// ServiceNameMapping — результат сопоставления свободного названия с каталогом.
+type ServiceNameMapping struct {
+	Name          string     `json:"name"`
+	CanonicalName string     `json:"canonical_name,omitempty"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty"`
+	Subscriptions int64      `json:"subscriptions"`
+}
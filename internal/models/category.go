@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Категории подписок. Набор фиксированный, чтобы разбивка по категориям
+// была сопоставимой у всех пользователей; для остального есть теги.
+const (
+	CategoryEntertainment = "entertainment"
+	CategorySoftware      = "software"
+	CategoryUtilities     = "utilities"
+	CategoryEducation     = "education"
+	CategoryOther         = "other"
+)
+
+var Categories = []string{CategoryEntertainment, CategorySoftware, CategoryUtilities, CategoryEducation, CategoryOther}
+
+func IsCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscriptionTag — произвольная метка подписки. В JSON выводится строкой.
+type SubscriptionTag struct {
+	SubscriptionID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Tag            string    `gorm:"primaryKey;index"`
+}
+
+func (t SubscriptionTag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Tag)
+}
+
+func (t *SubscriptionTag) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &t.Tag)
+}
+
+// NormalizeTags приводит теги к нижнему регистру, убирает пустые и повторы.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// Способы группировки в разбивке расходов.
+const (
+	GroupByService  = "service"
+	GroupByCategory = "category"
+	GroupByTag      = "tag"
+)
+
+// SubscriptionFilter — условия отбора подписок для списка и итогов.
+// Подписка должна иметь все перечисленные теги.
+type SubscriptionFilter struct {
+	ServiceName string
+	Category    string
+	Tags        []string
+	StartYM     *YearMonth
+	EndYM       *YearMonth
+}
+
+// GroupSpending — сумма по одной группе разбивки. Пустой Key — подписки без
+// категории или без тегов.
+type GroupSpending struct {
+	Key           string `json:"key"`
+	Total         int    `json:"total"`
+	Subscriptions int    `json:"subscriptions"`
+}
+
+// SpendingBreakdown — итог и, если задана группировка, суммы по группам.
+// Подписка с несколькими тегами входит в каждую из их групп.
+type SpendingBreakdown struct {
+	Total   int             `json:"total_price"`
+	GroupBy string          `json:"group_by,omitempty"`
+	Groups  []GroupSpending `json:"groups,omitempty"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventSubscriptionCreated      = "subscription.created"
+	EventSubscriptionUpdated      = "subscription.updated"
+	EventSubscriptionDeleted      = "subscription.deleted"
+	EventSubscriptionEnded        = "subscription.ended"
+	EventSubscriptionPriceChanged = "subscription.price_changed"
+	EventBudgetExceeded           = "budget.exceeded"
+)
+
+// WebhookEvents — события, на которые можно подписать webhook endpoint.
+var WebhookEvents = []string{
+	EventSubscriptionCreated,
+	EventSubscriptionUpdated,
+	EventSubscriptionDeleted,
+	EventSubscriptionEnded,
+	EventSubscriptionPriceChanged,
+	EventBudgetExceeded,
+}
+
+// Event — конверт доменного события. В таком виде оно уходит
+// и в тело webhook'а, и в сообщение брокера.
+type Event struct {
+	ID         uuid.UUID   `json:"id"`
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// PriceChangedData — данные события subscription.price_changed.
+type PriceChangedData struct {
+	Subscription  Subscription `json:"subscription"`
+	PreviousPrice int          `json:"previous_price"`
+}
+
+// OutboxEvent — событие, записанное в той же транзакции, что и изменение.
+// Relay публикует его в брокер и проставляет PublishedAt.
+type OutboxEvent struct {
+	ID          uuid.UUID       `json:"id" gorm:"type:uuid;primaryKey"`
+	AggregateID uuid.UUID       `json:"aggregate_id" gorm:"type:uuid;index"`
+	EventType   string          `json:"event_type"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:jsonb" swaggertype:"object"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"index"`
+	PublishedAt *time.Time      `json:"published_at,omitempty" gorm:"index"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Виды находок анализа подписок.
+const (
+	InsightPriceIncrease = "price_increase"
+	InsightDuplicate     = "duplicate"
+	InsightForgotten     = "forgotten"
+	InsightSpendingSpike = "spending_spike"
+)
+
+var InsightKinds = []string{InsightPriceIncrease, InsightDuplicate, InsightForgotten, InsightSpendingSpike}
+
+func IsInsightKind(s string) bool {
+	for _, kind := range InsightKinds {
+		if kind == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Insight — находка фонового анализа подписок пользователя. Key отличает
+// находки одного вида друг от друга: повторный анализ не создаёт ту же
+// находку заново, в том числе после того, как её скрыли.
+//
+// Month, Amount и PreviousAmount зависят от вида: для price_increase — месяц
+// и новая/прежняя цена, для spending_spike — месяц и расходы против среднего
+// за предыдущие месяцы, для forgotten — месяц начала и цена, для duplicate —
+// месяц начала и цена подписки, повторяющей RelatedID.
+type Insight struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;uniqueIndex:idx_insight_key"`
+	OrgID          *uuid.UUID `json:"org_id,omitempty" gorm:"type:uuid;index"`
+	Kind           string     `json:"kind" gorm:"uniqueIndex:idx_insight_key"`
+	Key            string     `json:"-" gorm:"uniqueIndex:idx_insight_key"`
+	SubscriptionID *uuid.UUID `json:"subscription_id,omitempty" gorm:"type:uuid;index"`
+	RelatedID      *uuid.UUID `json:"related_subscription_id,omitempty" gorm:"type:uuid"`
+	Month          YearMonth  `json:"month" swaggertype:"string" example:"2025-01"`
+	Amount         int        `json:"amount"`
+	PreviousAmount int        `json:"previous_amount,omitempty"`
+	Message        string     `json:"message"`
+	DismissedAt    *time.Time `json:"dismissed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// LogLevelRequest меняет уровень логирования во время работы сервиса.
+type LogLevelRequest struct {
+	// Component — handlers, services или db; пусто — глобальный уровень.
+	Component string `json:"component,omitempty" example:"db"`
+	// Level — debug, info, warn или error. Пустой уровень у компонента
+	// возвращает его к глобальному.
+	Level string `json:"level" example:"debug"`
+	// RevertAfter — через сколько вернуть прежний уровень, например 15m.
+	RevertAfter string `json:"revert_after,omitempty" example:"15m"`
+}
+
+type LogLevelResponse struct {
+	Level      string            `json:"level" example:"info"`
+	Components map[string]string `json:"components"`
+}
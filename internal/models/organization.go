@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// Organization — компания или рабочее пространство, которому принадлежат
+// пользователи и их подписки.
+type Organization struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Membership — участие пользователя в организации. Администраторы управляют
+// участниками и подписками всех участников.
+type Membership struct {
+	OrgID     uuid.UUID `json:"org_id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Tenant — от чьего имени выполняется запрос. Нулевой OrgID означает личное
+// пространство: подписки без организации, как до появления организаций.
+type Tenant struct {
+	OrgID  uuid.UUID
+	UserID uuid.UUID
+	Role   string
+}
+
+func (t Tenant) Personal() bool {
+	return t.OrgID == uuid.Nil
+}
+
+func (t Tenant) IsAdmin() bool {
+	return !t.Personal() && t.Role == RoleAdmin
+}
+
+// CreateOrganizationRequest represents запрос на создание организации
+// swagger:model CreateOrganizationRequest
+type CreateOrganizationRequest struct {
+	// Название организации
+	// required: true
+	Name string `json:"name" binding:"required"`
+}
+
+// SetMemberRequest represents запрос на добавление участника или смену его роли
+// swagger:model SetMemberRequest
+type SetMemberRequest struct {
+	// Роль: admin или member
+	// required: true
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+// UserSpending — сумма подписок одного пользователя.
+type UserSpending struct {
+	UserID uuid.UUID `json:"user_id"`
+	Total  int       `json:"total"`
+}
+
+// OrganizationTotals — суммы подписок организации в разрезе участников и сервисов.
+type OrganizationTotals struct {
+	Total     int               `json:"total"`
+	ByUser    []UserSpending    `json:"by_user"`
+	ByService []ServiceSpending `json:"by_service"`
+}
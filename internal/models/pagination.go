@@ -0,0 +1,7 @@
+package models
+
+// Pagination — параметры постраничной выборки. Limit = 0 означает «без ограничения».
+type Pagination struct {
+	Limit  int
+	Offset int
+}
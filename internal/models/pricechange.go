@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChange — запланированное изменение цены подписки с месяца
+// EffectiveFrom. Когда месяц наступает, цена подписки меняется и
+// проставляется AppliedAt; до этого изменение учитывается только в прогнозе.
+// Изменение цены через PATCH записывается сразу применённым, так что
+// применённые записи — история цен подписки.
+type PriceChange struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	SubscriptionID uuid.UUID `json:"subscription_id" gorm:"type:uuid;index"`
+	EffectiveFrom  YearMonth `json:"effective_from" swaggertype:"string" example:"2025-01"`
+	Price          int       `json:"price"`
+	// PreviousPrice — цена до применения; nil у ещё не применённых.
+	PreviousPrice *int       `json:"previous_price,omitempty"`
+	AppliedAt     *time.Time `json:"applied_at,omitempty" gorm:"index"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// SchedulePriceChangeRequest represents запрос на планирование изменения цены
+// swagger:model SchedulePriceChangeRequest
+type SchedulePriceChangeRequest struct {
+	// Новая цена в месяц
+	// required: true
+	Price *int `json:"price" binding:"required,min=0"`
+	// Месяц, с которого действует новая цена (формат: 01-2006), не раньше следующего
+	// required: true
+	EffectiveDate string `json:"effective_date" binding:"required"`
+}
+
+// ForecastMonth — прогноз расходов на месяц. Billed — списания в этом месяце
+// с учётом периода оплаты, Accrued — помесячная стоимость, Cumulative —
+// списания нарастающим итогом с начала прогноза.
+type ForecastMonth struct {
+	Month      YearMonth `json:"month" swaggertype:"string" example:"2025-01"`
+	Billed     int       `json:"billed"`
+	Accrued    int       `json:"accrued"`
+	Cumulative int       `json:"cumulative"`
+}
+
+// Forecast — прогноз расходов пользователя на несколько месяцев вперёд.
+type Forecast struct {
+	Months []ForecastMonth `json:"months"`
+	Total  int             `json:"total"`
+}
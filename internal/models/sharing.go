@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Правила разделения стоимости подписки. Плательщик — владелец подписки
+// (UserID); ему достаётся остаток после долей участников.
+const (
+	SplitEqual   = "equal"
+	SplitPercent = "percent"
+	SplitFixed   = "fixed"
+)
+
+// SubscriptionMember — пользователь, который делит подписку с плательщиком.
+// Percent используется при правиле percent, Amount — при fixed.
+type SubscriptionMember struct {
+	SubscriptionID uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey;index"`
+	Percent        int       `json:"percent,omitempty"`
+	Amount         int       `json:"amount,omitempty"`
+	CreatedAt      time.Time `json:"-"`
+}
+
+// SetMembersRequest represents запрос на изменение участников подписки
+// swagger:model SetMembersRequest
+type SetMembersRequest struct {
+	// Правило разделения: equal, percent или fixed. Пустой список участников отменяет разделение
+	// required: true
+	SplitRule string `json:"split_rule" binding:"required,oneof=equal percent fixed"`
+	// Участники без плательщика
+	// required: false
+	Members []MemberShareRequest `json:"members" binding:"dive"`
+}
+
+type MemberShareRequest struct {
+	// ID пользователя в формате UUID
+	// required: true
+	UserID string `json:"user_id" binding:"required,uuid"`
+	// Доля в процентах (для percent)
+	Percent int `json:"percent,omitempty" binding:"min=0,max=100"`
+	// Фиксированная сумма в месяц (для fixed)
+	Amount int `json:"amount,omitempty" binding:"min=0"`
+}
+
+// Share — часть ежемесячной стоимости подписки, которую несёт пользователь.
+type Share struct {
+	UserID uuid.UUID `json:"user_id"`
+	Amount int       `json:"amount"`
+}
+
+// Debt — сколько From должен To за месяц.
+type Debt struct {
+	From   uuid.UUID `json:"from"`
+	To     uuid.UUID `json:"to"`
+	Amount int       `json:"amount"`
+}
+
+// MonthlySettlement — взаимные долги по разделённым подпискам за месяц,
+// после взаимозачёта между каждой парой пользователей.
+type MonthlySettlement struct {
+	Month YearMonth `json:"month" swaggertype:"string" example:"2024-01"`
+	Debts []Debt    `json:"debts"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// MonthlySpending — сумма цен подписок, действовавших в месяце.
+type MonthlySpending struct {
+	Month YearMonth `json:"month" swaggertype:"string" example:"2024-01"`
+	Total int       `json:"total"`
+}
+
+// ServiceSpending — сумма по одному сервису.
+type ServiceSpending struct {
+	ServiceName string `json:"service_name"`
+	Total       int    `json:"total"`
+}
+
+// ServiceActivity — действующие в месяце подписки одного сервиса.
+type ServiceActivity struct {
+	ServiceName   string `json:"service_name"`
+	Subscriptions int    `json:"subscriptions"`
+	MonthlySpend  int    `json:"monthly_spend"`
+}
@@ -7,27 +7,60 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Subscription представляет модель подписки
 // swagger:model Subscription
 // SubscriptionSwagger — структура для отображения подписки в Swagger
 type SubscriptionSwagger struct {
-	ID           string  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	ServiceName  string  `json:"service_name" example:"Netflix"`
-	MonthlyPrice int     `json:"monthly_price" example:"1000"`
-	UserID       string  `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174001"`
-	StartedAt    string  `json:"started_at" example:"01-2024"`
-	EndedAt      *string `json:"ended_at,omitempty" example:"06-2024"`
+	ID            string                      `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ServiceName   string                      `json:"service_name" example:"Netflix"`
+	ServiceID     *string                     `json:"service_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174004"`
+	MonthlyPrice  int                         `json:"monthly_price" example:"1000"`
+	BillingPeriod int                         `json:"billing_period,omitempty" example:"12"`
+	Category      string                      `json:"category,omitempty" example:"entertainment"`
+	Tags          []string                    `json:"tags,omitempty" example:"family,weekend"`
+	UserID        string                      `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	OrgID         *string                     `json:"org_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+	StartedAt     string                      `json:"started_at" example:"01-2024"`
+	EndedAt       *string                     `json:"ended_at,omitempty" example:"06-2024"`
+	SplitRule     string                      `json:"split_rule,omitempty" example:"equal"`
+	Members       []SubscriptionMemberSwagger `json:"members,omitempty"`
+	UpdatedAt     *string                     `json:"updated_at,omitempty" example:"2024-06-15T10:00:00Z"`
+}
+
+type SubscriptionMemberSwagger struct {
+	UserID  string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	Percent int    `json:"percent,omitempty" example:"30"`
+	Amount  int    `json:"amount,omitempty" example:"300"`
 }
 
 type Subscription struct {
-	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
-	ServiceName  string     `json:"service_name"`
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	ServiceName string    `json:"service_name"`
+	// ServiceID — запись каталога, к которой сведено ServiceName; nil у названий вне каталога.
+	ServiceID    *uuid.UUID `json:"service_id,omitempty" gorm:"type:uuid;index"`
 	MonthlyPrice int        `json:"monthly_price"`
-	UserID       uuid.UUID  `json:"user_id"`
-	StartedAt    YearMonth  `json:"started_at"`
-	EndedAt      *YearMonth `json:"ended_at,omitempty"`
+	// BillingPeriod — период оплаты в месяцах (1, 3, 6 или 12), 0 — ежемесячно.
+	// MonthlyPrice всегда цена за месяц, списание — MonthlyPrice * период.
+	BillingPeriod int `json:"billing_period,omitempty"`
+	// Category — одна из Categories; пусто — без категории.
+	Category string            `json:"category,omitempty" gorm:"index"`
+	Tags     []SubscriptionTag `json:"tags,omitempty" gorm:"foreignKey:SubscriptionID"`
+	UserID   uuid.UUID         `json:"user_id"`
+	// OrgID — организация-владелец; nil у подписок личного пространства.
+	OrgID     *uuid.UUID `json:"org_id,omitempty" gorm:"type:uuid;index"`
+	StartedAt YearMonth  `json:"started_at"`
+	EndedAt   *YearMonth `json:"ended_at,omitempty"`
+	// SplitRule — как стоимость делится между плательщиком и Members; пусто — не делится.
+	SplitRule string               `json:"split_rule,omitempty"`
+	Members   []SubscriptionMember `json:"members,omitempty" gorm:"foreignKey:SubscriptionID"`
+	// UpdatedAt — время последнего изменения; nil у подписок, не менявшихся
+	// с тех пор, как оно стало сохраняться.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// Удалённые подписки остаются в базе до команды purge.
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 type YearMonth struct {
@@ -35,6 +68,50 @@ type YearMonth struct {
 	Month time.Month
 }
 
+func (ym YearMonth) String() string {
+	return fmt.Sprintf("%04d-%02d", ym.Year, ym.Month)
+}
+
+// Index — порядковый номер месяца, удобный для сравнения и арифметики.
+func (ym YearMonth) Index() int {
+	return ym.Year*12 + int(ym.Month) - 1
+}
+
+func (ym YearMonth) AddMonths(n int) YearMonth {
+	i := ym.Index() + n
+	return YearMonth{Year: i / 12, Month: time.Month(i%12 + 1)}
+}
+
+func (ym YearMonth) Before(other YearMonth) bool {
+	return ym.Index() < other.Index()
+}
+
+func (ym YearMonth) After(other YearMonth) bool {
+	return ym.Index() > other.Index()
+}
+
+// Period — период оплаты в месяцах, не меньше одного.
+func (s Subscription) Period() int {
+	if s.BillingPeriod < 1 {
+		return 1
+	}
+	return s.BillingPeriod
+}
+
+// BilledIn сообщает, приходится ли на месяц списание: первое — в месяц
+// начала, дальше через каждые Period месяцев.
+func (s Subscription) BilledIn(ym YearMonth) bool {
+	return s.ActiveIn(ym) && (ym.Index()-s.StartedAt.Index())%s.Period() == 0
+}
+
+// ActiveIn сообщает, действует ли подписка в указанном месяце.
+func (s Subscription) ActiveIn(ym YearMonth) bool {
+	if s.StartedAt.After(ym) {
+		return false
+	}
+	return s.EndedAt == nil || !s.EndedAt.Before(ym)
+}
+
 func (ym YearMonth) GormDataType() string {
 	return "timestamp"
 }
@@ -67,7 +144,7 @@ func (ym *YearMonth) Scan(value interface{}) error {
 }
 
 func (ym YearMonth) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf(`"%04d-%02d"`, ym.Year, ym.Month)), nil
+	return []byte(`"` + ym.String() + `"`), nil
 }
 
 func (ym *YearMonth) UnmarshalJSON(data []byte) error {
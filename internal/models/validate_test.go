@@ -0,0 +1,66 @@
+package models
+
+import "testing"
+
+func TestCreateSubscriptionRequestValidate(t *testing.T) {
+	valid := func() CreateSubscriptionRequest {
+		return CreateSubscriptionRequest{
+			ServiceName: "Netflix",
+			Price:       599,
+			UserID:      "123e4567-e89b-12d3-a456-426614174001",
+			StartDate:   "01-2025",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*CreateSubscriptionRequest)
+		wantErr bool
+	}{
+		{"valid", func(*CreateSubscriptionRequest) {}, false},
+		{"plan without price", func(r *CreateSubscriptionRequest) { r.Price, r.Plan = 0, "premium" }, false},
+		{"zero price without plan", func(r *CreateSubscriptionRequest) { r.Price = 0 }, true},
+		{"negative price", func(r *CreateSubscriptionRequest) { r.Price = -1 }, true},
+		{"no service name", func(r *CreateSubscriptionRequest) { r.ServiceName = "" }, true},
+		{"invalid user ID", func(r *CreateSubscriptionRequest) { r.UserID = "user" }, true},
+		{"no start date", func(r *CreateSubscriptionRequest) { r.StartDate = "" }, true},
+		{"quarterly", func(r *CreateSubscriptionRequest) { r.BillingPeriod = 3 }, false},
+		{"unknown billing period", func(r *CreateSubscriptionRequest) { r.BillingPeriod = 2 }, true},
+		{"known category", func(r *CreateSubscriptionRequest) { r.Category = CategorySoftware }, false},
+		{"unknown category", func(r *CreateSubscriptionRequest) { r.Category = "games" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(&req)
+			if err := req.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateSubscriptionRequestValidate(t *testing.T) {
+	period := func(p int) *int { return &p }
+	category := func(c string) *string { return &c }
+
+	tests := []struct {
+		name    string
+		req     UpdateSubscriptionRequest
+		wantErr bool
+	}{
+		{"empty", UpdateSubscriptionRequest{}, false},
+		{"yearly", UpdateSubscriptionRequest{BillingPeriod: period(12)}, false},
+		{"zero billing period", UpdateSubscriptionRequest{BillingPeriod: period(0)}, true},
+		{"unknown billing period", UpdateSubscriptionRequest{BillingPeriod: period(5)}, true},
+		{"clear category", UpdateSubscriptionRequest{Category: category("")}, false},
+		{"unknown category", UpdateSubscriptionRequest{Category: category("games")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
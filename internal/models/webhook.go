@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// WebhookEndpoint — зарегистрированный получатель событий.
+// Пустой список Events означает подписку на все события.
+type WebhookEndpoint struct {
+	ID  uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	URL string    `json:"url"`
+	// OrgID — организация, события которой получает endpoint; nil — события
+	// личного пространства.
+	OrgID     *uuid.UUID `json:"org_id,omitempty" gorm:"type:uuid;index"`
+	Secret    string     `json:"-"`
+	Events    []string   `json:"events" gorm:"serializer:json"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Accepts сообщает, нужно ли доставлять событие на этот endpoint.
+func (e WebhookEndpoint) Accepts(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, ev := range e.Events {
+		if ev == event || ev == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery — одна попытка (с повторами) доставить событие на endpoint.
+// Доставки в статусе failed образуют dead-letter очередь.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id" gorm:"type:uuid;primaryKey"`
+	EndpointID     uuid.UUID       `json:"endpoint_id" gorm:"type:uuid;index"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" gorm:"type:jsonb" swaggertype:"object"`
+	Status         string          `json:"status" gorm:"index"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" gorm:"index"`
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,44 @@
+package outbox
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher принимает список брокеров через запятую.
+func NewKafkaPublisher(brokers, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(strings.Split(brokers, ",")...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+			// Relay публикует по одному сообщению и ждёт подтверждения; с
+			// BatchTimeout по умолчанию (1s) каждое событие ждало бы секунду.
+			BatchTimeout: 5 * time.Millisecond,
+		},
+	}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, msg Message) error {
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(msg.Key),
+		Value: msg.Payload,
+		Headers: []kafka.Header{
+			{Key: "event_id", Value: []byte(msg.ID.String())},
+			{Key: "event_type", Value: []byte(msg.Type)},
+		},
+	})
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
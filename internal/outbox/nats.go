@@ -0,0 +1,43 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSPublisher публикует в JetStream: обычный NATS publish не даёт
+// подтверждения, а без него relay не может считать событие доставленным.
+// Сообщения уходят в subject <subject>.<тип события>, ID события
+// используется для дедупликации на стороне JetStream.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream
+	subject string
+}
+
+func NewNATSPublisher(url, subject string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	return &NATSPublisher{conn: conn, js: js, subject: subject}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, msg Message) error {
+	_, err := p.js.Publish(ctx, p.subject+"."+msg.Type, msg.Payload, jetstream.WithMsgID(msg.ID.String()))
+	return err
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+	"subscribers/internal/models"
+	"subscribers/logger"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pruner удаляет события outbox старше retention: опубликованные всегда, а
+// неопубликованные — только если брокер не настроен и relay их никогда не
+// заберёт.
+type Pruner struct {
+	db          *gorm.DB
+	retention   time.Duration
+	unpublished bool
+	interval    time.Duration
+	running     atomic.Bool
+}
+
+func NewPruner(db *gorm.DB, retention time.Duration, unpublished bool) *Pruner {
+	if retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+	return &Pruner{db: db, retention: retention, unpublished: unpublished, interval: time.Hour}
+}
+
+// Run чистит таблицу сразу и затем раз в час, пока не будет отменён ctx.
+func (p *Pruner) Run(ctx context.Context) {
+	p.running.Store(true)
+	defer p.running.Store(false)
+
+	logger.SugaredLogger.Info("Outbox pruner started")
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := Prune(p.db.WithContext(ctx), time.Now().UTC().Add(-p.retention), p.unpublished)
+		if err != nil {
+			logger.SugaredLogger.Errorf("Failed to prune outbox events: %v", err)
+		} else if deleted > 0 {
+			logger.SugaredLogger.Infof("Pruned %d outbox events", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.SugaredLogger.Info("Outbox pruner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Pruner) Running() bool {
+	return p.running.Load()
+}
+
+// Prune удаляет опубликованные до before события, а с unpublished — и
+// неопубликованные, созданные до before.
+func Prune(db *gorm.DB, before time.Time, unpublished bool) (int64, error) {
+	query := db.Where("published_at IS NOT NULL AND published_at <= ?", before)
+	if unpublished {
+		query = query.Or("published_at IS NULL AND created_at <= ?", before)
+	}
+	result := query.Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
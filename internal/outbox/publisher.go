@@ -0,0 +1,42 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Message — событие outbox в виде, пригодном для брокера.
+// Key — ID агрегата: события одной подписки попадают в одну партицию.
+type Message struct {
+	ID      uuid.UUID
+	Type    string
+	Key     string
+	Payload []byte
+}
+
+// Publisher отправляет сообщения в брокер. Publish должен вернуть nil
+// только после того, как брокер подтвердил приём сообщения.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+type PublisherConfig struct {
+	Type  string
+	URL   string
+	Topic string
+}
+
+// NewPublisher создаёт Publisher по типу брокера: kafka или nats.
+func NewPublisher(cfg PublisherConfig) (Publisher, error) {
+	switch cfg.Type {
+	case "kafka":
+		return NewKafkaPublisher(cfg.URL, cfg.Topic), nil
+	case "nats":
+		return NewNATSPublisher(cfg.URL, cfg.Topic)
+	default:
+		return nil, fmt.Errorf("unknown broker type: %q", cfg.Type)
+	}
+}
@@ -0,0 +1,40 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Record сохраняет доменное событие в таблицу outbox.
+// db должен быть транзакцией, в которой выполняется само изменение.
+func Record(db *gorm.DB, eventType string, aggregateID uuid.UUID, data interface{}) error {
+	id := uuid.New()
+	now := time.Now().UTC()
+
+	payload, err := json.Marshal(models.Event{
+		ID:         id,
+		Event:      eventType,
+		OccurredAt: now,
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox event: %w", err)
+	}
+
+	event := models.OutboxEvent{
+		ID:          id,
+		AggregateID: aggregateID,
+		EventType:   eventType,
+		Payload:     payload,
+		CreatedAt:   now,
+	}
+	if err := db.Create(&event).Error; err != nil {
+		return fmt.Errorf("failed to save outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"subscribers/internal/models"
+	"subscribers/logger"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Relay struct {
+	db           *gorm.DB
+	publisher    Publisher
+	pollInterval time.Duration
+	batchSize    int
+	running      atomic.Bool
+}
+
+func NewRelay(db *gorm.DB, publisher Publisher, pollInterval time.Duration) *Relay {
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	return &Relay{
+		db:           db,
+		publisher:    publisher,
+		pollInterval: pollInterval,
+		batchSize:    100,
+	}
+}
+
+// Run переносит события из outbox в брокер, пока не будет отменён ctx.
+// Если брокер недоступен, события остаются в таблице и публикуются
+// после его восстановления.
+func (r *Relay) Run(ctx context.Context) {
+	r.running.Store(true)
+	defer r.running.Store(false)
+
+	logger.SugaredLogger.Info("Outbox relay started")
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			published, err := r.publishBatch(ctx)
+			if err != nil {
+				logger.SugaredLogger.Warnf("Outbox relay error: %v", err)
+			}
+			if err != nil || published < r.batchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.SugaredLogger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) Running() bool {
+	return r.running.Load()
+}
+
+// publishBatch публикует неотправленные события в порядке создания.
+// На первой ошибке пачка прерывается, чтобы не нарушить порядок событий.
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	published := 0
+	var publishErr error
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var events []models.OutboxEvent
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL").
+			Order("created_at").
+			Limit(r.batchSize).
+			Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			err := r.publisher.Publish(ctx, Message{
+				ID:      event.ID,
+				Type:    event.EventType,
+				Key:     event.AggregateID.String(),
+				Payload: event.Payload,
+			})
+			if err != nil {
+				publishErr = fmt.Errorf("failed to publish event %s: %w", event.ID, err)
+				tx.Model(&models.OutboxEvent{}).
+					Where("id = ?", event.ID).
+					Updates(map[string]interface{}{
+						"attempts":   event.Attempts + 1,
+						"last_error": err.Error(),
+					})
+				return nil
+			}
+
+			err = tx.Model(&models.OutboxEvent{}).
+				Where("id = ?", event.ID).
+				Updates(map[string]interface{}{
+					"attempts":     event.Attempts + 1,
+					"published_at": time.Now().UTC(),
+					"last_error":   "",
+				}).Error
+			if err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+
+	if err == nil {
+		err = publishErr
+	}
+	return published, err
+}
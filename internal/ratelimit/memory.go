@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval — как часто MemoryStore удаляет наполнившиеся корзины,
+// чтобы память не росла с числом разных клиентов.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// MemoryStore держит корзины в памяти процесса. Лимит считается отдельно
+// на каждом экземпляре сервиса.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Burst), last: now, limit: limit}
+		s.buckets[key] = b
+	}
+
+	var res Result
+	b.tokens, res = take(b.tokens, now.Sub(b.last), limit)
+	b.last = now
+	return res, nil
+}
+
+// sweep удаляет корзины, которые к моменту now уже наполнились бы:
+// новая корзина для того же ключа ничем от них не отличается.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		full := b.tokens + now.Sub(b.last).Seconds()*b.limit.Rate
+		if full >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
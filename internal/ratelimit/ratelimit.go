@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit — параметры token bucket: Burst запросов подряд, затем Rate в секунду.
+// Нулевой Limit отключает ограничение.
+type Limit struct {
+	Rate  float64
+	Burst int
+	// Period — окно из исходной записи лимита, нужно только для RateLimit-Policy.
+	Period time.Duration
+}
+
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Period)
+}
+
+// ParseLimit разбирает лимит вида "100/1m" или "10/s": не больше 100 запросов
+// за минуту, допускается всплеск до 100. Пустая строка и "off" отключают лимит.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "off" {
+		return Limit{}, nil
+	}
+
+	countStr, periodStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <count>/<period>", s)
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: count must be a positive integer", s)
+	}
+	if periodStr != "" && (periodStr[0] < '0' || periodStr[0] > '9') {
+		periodStr = "1" + periodStr
+	}
+	period, err := time.ParseDuration(periodStr)
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: bad period", s)
+	}
+
+	return Limit{
+		Rate:   float64(count) / period.Seconds(),
+		Burst:  count,
+		Period: period,
+	}, nil
+}
+
+// Result — итог попытки взять токен.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter — через сколько появится следующий токен, если запрос отклонён.
+	RetryAfter time.Duration
+	// Reset — через сколько корзина наполнится полностью.
+	Reset time.Duration
+}
+
+// Store хранит корзины. Реализации должны быть безопасны для конкурентного
+// использования; Redis позволяет делить лимит между несколькими экземплярами.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take — арифметика token bucket: пополняет корзину за прошедшее время
+// и пытается списать один токен.
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	tokens = min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return tokens, result(tokens, allowed, limit)
+}
+
+// result описывает состояние корзины, в которой осталось tokens токенов.
+func result(tokens float64, allowed bool, limit Limit) Result {
+	res := Result{
+		Allowed:   allowed,
+		Remaining: int(tokens),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
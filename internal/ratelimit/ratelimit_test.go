@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "100/1m", want: Limit{Rate: 100.0 / 60, Burst: 100, Period: time.Minute}},
+		{in: "10/s", want: Limit{Rate: 10, Burst: 10, Period: time.Second}},
+		{in: " 30/1h ", want: Limit{Rate: 30.0 / 3600, Burst: 30, Period: time.Hour}},
+		{in: "off"},
+		{in: ""},
+		{in: "100", wantErr: true},
+		{in: "0/1m", wantErr: true},
+		{in: "-5/1m", wantErr: true},
+		{in: "many/1m", wantErr: true},
+		{in: "10/0s", wantErr: true},
+		{in: "10/fortnight", wantErr: true},
+		{in: "10/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLimit(%q) error = %v, want error: %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLimit(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if !tt.wantErr && got.Enabled() != (tt.want.Burst > 0) {
+				t.Errorf("Enabled() = %v", got.Enabled())
+			}
+		})
+	}
+}
+
+func TestTake(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 4, Period: 2 * time.Second}
+
+	tests := []struct {
+		name       string
+		tokens     float64
+		elapsed    time.Duration
+		wantTokens float64
+		want       Result
+	}{
+		{
+			name:       "full bucket",
+			tokens:     4,
+			wantTokens: 3,
+			want:       Result{Allowed: true, Remaining: 3, Reset: 500 * time.Millisecond},
+		},
+		{
+			name:       "refill is capped at burst",
+			tokens:     1,
+			elapsed:    time.Hour,
+			wantTokens: 3,
+			want:       Result{Allowed: true, Remaining: 3, Reset: 500 * time.Millisecond},
+		},
+		{
+			name:       "empty bucket",
+			tokens:     0,
+			wantTokens: 0,
+			want:       Result{Allowed: false, Remaining: 0, RetryAfter: 500 * time.Millisecond, Reset: 2 * time.Second},
+		},
+		{
+			name:       "partially refilled",
+			tokens:     0,
+			elapsed:    250 * time.Millisecond,
+			wantTokens: 0.5,
+			want:       Result{Allowed: false, Remaining: 0, RetryAfter: 250 * time.Millisecond, Reset: 1750 * time.Millisecond},
+		},
+		{
+			name:       "refilled enough for one request",
+			tokens:     0.5,
+			elapsed:    250 * time.Millisecond,
+			wantTokens: 0,
+			want:       Result{Allowed: true, Remaining: 0, Reset: 2 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, res := take(tt.tokens, tt.elapsed, limit)
+			if tokens != tt.wantTokens {
+				t.Errorf("tokens = %v, want %v", tokens, tt.wantTokens)
+			}
+			if res != tt.want {
+				t.Errorf("result = %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now, store.lastSweep = func() time.Time { return now }, now
+	limit := Limit{Rate: 1, Burst: 2, Period: 2 * time.Second}
+
+	allowed := func(key string, limit Limit) bool {
+		t.Helper()
+		res, err := store.Take(ctx, key, limit)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		return res.Allowed
+	}
+
+	if !allowed("a", limit) || !allowed("a", limit) {
+		t.Fatal("the first burst requests must be allowed")
+	}
+	if allowed("a", limit) {
+		t.Fatal("a request over the burst must be rejected")
+	}
+	if !allowed("b", limit) {
+		t.Fatal("another key must have its own bucket")
+	}
+
+	now = now.Add(time.Second)
+	if !allowed("a", limit) {
+		t.Fatal("a request must be allowed after a token is refilled")
+	}
+	if allowed("a", limit) {
+		t.Fatal("only one token must be refilled in a second")
+	}
+
+	// Новый лимит начинает с полной корзины.
+	if !allowed("a", Limit{Rate: 1, Burst: 3, Period: 3 * time.Second}) {
+		t.Fatal("a changed limit must start with a full bucket")
+	}
+
+	now = now.Add(sweepInterval)
+	store.Take(ctx, "c", limit)
+	if _, ok := store.buckets["b"]; ok {
+		t.Error("refilled buckets must be swept")
+	}
+}
@@ -0,0 +1,151 @@
+package router
+
+import (
+	"fmt"
+	"subscribers/internal/certs"
+	"subscribers/internal/graphqlapi"
+	"subscribers/internal/handlers"
+	"subscribers/internal/health"
+	"subscribers/internal/metrics"
+	"subscribers/internal/middleware"
+	"subscribers/internal/ratelimit"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"gorm.io/gorm"
+)
+
+type Dependencies struct {
+	DB      *gorm.DB
+	GraphQL *graphqlapi.Executor
+	Health  *health.Checker
+	// Service — имя сервиса в трассировке.
+	Service string
+	// AdminToken открывает доступ к /admin. Пустой токен отключает эти маршруты.
+	AdminToken string
+	// AdminIdentities — сервисы, которым /admin доступен по клиентскому сертификату.
+	AdminIdentities []string
+	// UserIdentities — сервисы, которым доверяется пользователь из X-User-ID.
+	UserIdentities []string
+	// TrustedProxies — адреса и подсети прокси, чьим X-Forwarded-For
+	// доверяется при определении IP клиента. Пустой список — не доверять никому.
+	TrustedProxies []string
+	// Identities сопоставляет клиентские сертификаты с сервисами (mTLS).
+	Identities certs.Identities
+	// RateLimiter хранит корзины лимитов; nil отключает ограничение.
+	RateLimiter ratelimit.Store
+	RateLimits  RateLimits
+
+	CORS         middleware.CORSOptions
+	HSTSMaxAge   time.Duration
+	MaxBodyBytes int64
+}
+
+// RateLimits — лимиты по группам маршрутов. /subscriptions/total и GraphQL
+// дороже остальных запросов, поэтому ограничиваются отдельно.
+type RateLimits struct {
+	API     ratelimit.Limit
+	Total   ratelimit.Limit
+	GraphQL ratelimit.Limit
+}
+
+// New регистрирует все HTTP маршруты сервиса.
+func New(deps Dependencies) (*gin.Engine, error) {
+	router := gin.New()
+	if err := router.SetTrustedProxies(deps.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	router.Use(otelgin.Middleware(deps.Service))
+	router.Use(middleware.RequestLogger())
+	router.Use(middleware.Recovery())
+	router.Use(middleware.ClientIdentity(deps.Identities))
+	router.Use(metrics.Middleware())
+	router.Use(middleware.SecurityHeaders(deps.HSTSMaxAge))
+	router.Use(middleware.CORS(deps.CORS))
+	router.Use(middleware.BodyLimit(deps.MaxBodyBytes))
+
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	router.GET("/healthz", handlers.HealthzHandler())
+	router.GET("/readyz", handlers.ReadyzHandler(deps.Health))
+
+	router.Static("/docs", "./docs")
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	user := middleware.ActingUser(deps.UserIdentities)
+	tenant := middleware.Tenant(deps.DB)
+
+	api := router.Group("", user, middleware.RateLimit(deps.RateLimiter, "api", deps.RateLimits.API), tenant)
+	api.POST("/createSubscription", handlers.CreateSubscriptionHandler(deps.DB))
+	api.GET("/subscriptions", handlers.GetSubscriptionsHandler(deps.DB))
+	api.GET("/subscriptions/:id", handlers.GetSubscriptionHandler(deps.DB))
+	api.PATCH("/subscriptions/:id", handlers.UpdateSubscriptionHandler(deps.DB))
+	api.DELETE("/subscriptions/:id", handlers.DeleteSubscriptionHandler(deps.DB))
+	api.PUT("/subscriptions/:id/members", handlers.SetSubscriptionMembersHandler(deps.DB))
+	api.GET("/subscriptions/settlements", handlers.GetSettlementsHandler(deps.DB))
+	api.GET("/subscriptions/forecast", handlers.GetForecastHandler(deps.DB))
+	api.POST("/subscriptions/:id/price-changes", handlers.SchedulePriceChangeHandler(deps.DB))
+	api.GET("/subscriptions/:id/price-changes", handlers.GetPriceChangesHandler(deps.DB))
+	api.DELETE("/subscriptions/:id/price-changes/:change_id", handlers.DeletePriceChangeHandler(deps.DB))
+
+	api.POST("/organizations", handlers.CreateOrganizationHandler(deps.DB))
+	api.GET("/organizations/:org_id/members", handlers.GetMembersHandler(deps.DB))
+	api.PUT("/organizations/:org_id/members/:user_id", handlers.SetMemberHandler(deps.DB))
+	api.DELETE("/organizations/:org_id/members/:user_id", handlers.RemoveMemberHandler(deps.DB))
+	api.GET("/organizations/:org_id/totals", handlers.GetOrganizationTotalsHandler(deps.DB))
+
+	api.POST("/budgets", handlers.CreateBudgetHandler(deps.DB))
+	api.GET("/budgets", handlers.GetBudgetsHandler(deps.DB))
+	api.GET("/budgets/status", handlers.GetBudgetStatusHandler(deps.DB))
+	api.PATCH("/budgets/:id", handlers.UpdateBudgetHandler(deps.DB))
+	api.DELETE("/budgets/:id", handlers.DeleteBudgetHandler(deps.DB))
+
+	api.GET("/insights", handlers.GetInsightsHandler(deps.DB))
+	api.POST("/insights/:id/dismiss", handlers.DismissInsightHandler(deps.DB))
+
+	api.GET("/catalog/services", handlers.GetCatalogServicesHandler(deps.DB))
+	api.GET("/catalog/services/:id", handlers.GetCatalogServiceHandler(deps.DB))
+
+	api.POST("/webhooks", handlers.CreateWebhookHandler(deps.DB))
+	api.GET("/webhooks", handlers.GetWebhooksHandler(deps.DB))
+	api.DELETE("/webhooks/:id", handlers.DeleteWebhookHandler(deps.DB))
+	api.GET("/webhooks/deliveries/failed", handlers.GetFailedWebhookDeliveriesHandler(deps.DB))
+	api.POST("/webhooks/deliveries/:id/redeliver", handlers.RedeliverWebhookHandler(deps.DB))
+
+	router.GET("/subscriptions/total", user,
+		middleware.RateLimit(deps.RateLimiter, "total", deps.RateLimits.Total), tenant,
+		handlers.GetSubscriptionsTotalHandler(deps.DB))
+
+	router.POST("/graphql", user,
+		middleware.RateLimit(deps.RateLimiter, "graphql", deps.RateLimits.GraphQL), tenant,
+		handlers.GraphQLHandler(deps.GraphQL))
+
+	admin := router.Group("/admin", middleware.AdminAuth(deps.AdminToken, deps.AdminIdentities))
+	admin.GET("/log-level", handlers.GetLogLevelHandler())
+	admin.PUT("/log-level", handlers.SetLogLevelHandler())
+	admin.POST("/catalog/services", handlers.CreateCatalogServiceHandler(deps.DB))
+	admin.PUT("/catalog/services/:id", handlers.UpdateCatalogServiceHandler(deps.DB))
+	admin.DELETE("/catalog/services/:id", handlers.DeleteCatalogServiceHandler(deps.DB))
+
+	router.GET("/", func(ctx *gin.Context) {
+		ctx.Redirect(302, "/swagger/index.html")
+	})
+
+	return router, nil
+}
+
+// NewHealth — маршруты отдельного сервера проверок состояния. Он работает
+// без TLS и middleware основного роутера, поэтому проверки контейнера не
+// зависят от сертификатов, CORS и лимитов.
+func NewHealth(checker *health.Checker) *gin.Engine {
+	router := gin.New()
+	// Пустой список не может быть ошибочным.
+	_ = router.SetTrustedProxies(nil)
+	router.Use(middleware.Recovery())
+	router.GET("/healthz", handlers.HealthzHandler())
+	router.GET("/readyz", handlers.ReadyzHandler(checker))
+	return router
+}
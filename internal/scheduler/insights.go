@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"subscribers/internal/services"
+	"subscribers/logger"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Insights периодически анализирует подписки и сохраняет новые находки.
+type Insights struct {
+	db       *gorm.DB
+	interval time.Duration
+	running  atomic.Bool
+}
+
+func NewInsights(db *gorm.DB, interval time.Duration) *Insights {
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+	return &Insights{db: db, interval: interval}
+}
+
+// Run анализирует подписки сразу и затем раз в interval, пока не будет отменён ctx.
+func (i *Insights) Run(ctx context.Context) {
+	i.running.Store(true)
+	defer i.running.Store(false)
+
+	logger.SugaredLogger.Info("Subscription analysis started")
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := services.AnalyzeSubscriptions(i.db.WithContext(ctx), time.Now().UTC())
+		if err != nil {
+			logger.SugaredLogger.Errorf("Failed to analyze subscriptions: %v", err)
+		} else if created > 0 {
+			logger.SugaredLogger.Infof("Subscription analysis found %d new insights", created)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.SugaredLogger.Info("Subscription analysis stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (i *Insights) Running() bool {
+	return i.running.Load()
+}
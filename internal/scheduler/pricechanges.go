@@ -0,0 +1,56 @@
+// Package scheduler — периодические задачи над подписками.
+package scheduler
+
+import (
+	"context"
+	"subscribers/internal/services"
+	"subscribers/logger"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PriceChanges применяет запланированные изменения цены, месяц которых наступил.
+type PriceChanges struct {
+	db       *gorm.DB
+	interval time.Duration
+	running  atomic.Bool
+}
+
+func NewPriceChanges(db *gorm.DB, interval time.Duration) *PriceChanges {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &PriceChanges{db: db, interval: interval}
+}
+
+// Run проверяет изменения сразу и затем раз в interval, пока не будет отменён ctx.
+func (p *PriceChanges) Run(ctx context.Context) {
+	p.running.Store(true)
+	defer p.running.Store(false)
+
+	logger.SugaredLogger.Info("Price change scheduler started")
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := services.ApplyDuePriceChanges(p.db.WithContext(ctx), time.Now().UTC())
+		if err != nil {
+			logger.SugaredLogger.Errorf("Failed to apply price changes: %v", err)
+		} else if applied > 0 {
+			logger.SugaredLogger.Infof("Applied %d scheduled price changes", applied)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.SugaredLogger.Info("Price change scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *PriceChanges) Running() bool {
+	return p.running.Load()
+}
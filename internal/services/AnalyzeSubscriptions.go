@@ -0,0 +1,141 @@
+package services
+
+import (
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnalyzeSubscriptions ищет повышения цены, дубликаты, забытые подписки и
+// скачки расходов и сохраняет новые находки. Уже найденные, в том числе
+// скрытые, повторно не создаются. Подписки загружаются пачками по
+// analyzeBatchUsers пользователей: в пачку попадают подписки, которые
+// пользователи оплачивают или делят с плательщиком, чтобы скачок расходов
+// участника считался по всем его долям.
+func AnalyzeSubscriptions(db *gorm.DB, now time.Time) (created int, err error) {
+	db, span := startSpan(db, "AnalyzeSubscriptions")
+	defer func() { endSpan(span, err) }()
+
+	current := models.YearMonth{Year: now.Year(), Month: now.Month()}
+
+	var changes []models.PriceChange
+	err = db.Where("applied_at >= ? AND price > previous_price", now.Add(-priceIncreaseLookback)).
+		Find(&changes).Error
+	if err != nil {
+		return 0, err
+	}
+	changed := make(map[uuid.UUID]models.Subscription)
+	if len(changes) > 0 {
+		ids := make([]uuid.UUID, 0, len(changes))
+		for _, change := range changes {
+			ids = append(ids, change.SubscriptionID)
+		}
+		var changedSubs []models.Subscription
+		if err := db.Where("id IN ?", ids).Find(&changedSubs).Error; err != nil {
+			return 0, err
+		}
+		for _, sub := range changedSubs {
+			changed[sub.ID] = sub
+		}
+	}
+	created, err = saveInsights(db, priceIncreaseInsights(changes, changed))
+	if err != nil {
+		return created, err
+	}
+
+	var serviceAliases []models.ServiceAlias
+	if err := db.Find(&serviceAliases).Error; err != nil {
+		return created, err
+	}
+	aliases := make(map[string]uuid.UUID, len(serviceAliases))
+	for _, alias := range serviceAliases {
+		aliases[alias.Alias] = alias.ServiceID
+	}
+
+	active := func(db *gorm.DB) *gorm.DB {
+		return db.Where("started_at <= ? AND (ended_at IS NULL OR ended_at >= ?)", current, current.AddMonths(-spikeTrailingMonths))
+	}
+	payers := db.Model(&models.Subscription{}).Scopes(active).Select("user_id")
+	members := db.Model(&models.SubscriptionMember{}).
+		Where("subscription_id IN (?)", db.Model(&models.Subscription{}).Scopes(active).Select("id")).
+		Select("user_id")
+
+	var last uuid.UUID
+	for {
+		var userIDs []uuid.UUID
+		err := db.Table("(? UNION ?) AS involved", payers, members).
+			Where("user_id > ?", last).
+			Order("user_id").
+			Limit(analyzeBatchUsers).
+			Pluck("user_id", &userIDs).Error
+		if err != nil {
+			return created, err
+		}
+		if len(userIDs) == 0 {
+			return created, nil
+		}
+		last = userIDs[len(userIDs)-1]
+
+		var subs []models.Subscription
+		err = db.Scopes(active, involvingAny(userIDs)).Preload("Members").Find(&subs).Error
+		if err != nil {
+			return created, err
+		}
+
+		// Подписку оценивает пачка её плательщика, скачок — пачка пользователя.
+		batch := make(map[uuid.UUID]bool, len(userIDs))
+		for _, id := range userIDs {
+			batch[id] = true
+		}
+		var owned []models.Subscription
+		for _, sub := range subs {
+			if batch[sub.UserID] {
+				owned = append(owned, sub)
+			}
+		}
+		var insights []models.Insight
+		insights = append(insights, duplicateInsights(owned, aliases, current)...)
+		insights = append(insights, forgottenInsights(owned, now)...)
+		for _, insight := range spikeInsights(subs, current) {
+			if batch[insight.UserID] {
+				insights = append(insights, insight)
+			}
+		}
+
+		n, err := saveInsights(db, insights)
+		created += n
+		if err != nil {
+			return created, err
+		}
+		if len(userIDs) < analyzeBatchUsers {
+			return created, nil
+		}
+	}
+}
+
+// involvingAny — подписки, которые хотя бы один из userIDs оплачивает или
+// делит с плательщиком.
+func involvingAny(userIDs []uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"user_id IN ? OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id IN ?)",
+			userIDs, userIDs,
+		)
+	}
+}
+
+// saveInsights сохраняет находки, пропуская уже существующие, и возвращает
+// число новых.
+func saveInsights(db *gorm.DB, insights []models.Insight) (int, error) {
+	if len(insights) == 0 {
+		return 0, nil
+	}
+	for i := range insights {
+		insights[i].ID = uuid.New()
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&insights, 100)
+	return int(result.RowsAffected), result.Error
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"subscribers/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ApplyDuePriceChanges применяет изменения цены, месяц которых наступил:
+// меняет цену подписки и публикует subscription.price_changed. Каждое
+// изменение применяется в своей транзакции; строки блокируются, поэтому
+// несколько экземпляров сервиса не применят одно изменение дважды.
+func ApplyDuePriceChanges(db *gorm.DB, now time.Time) (applied int, err error) {
+	db, span := startSpan(db, "ApplyDuePriceChanges")
+	defer func() { endSpan(span, err) }()
+
+	current := models.YearMonth{Year: now.Year(), Month: now.Month()}
+
+	var due []models.PriceChange
+	err = db.Where("applied_at IS NULL AND effective_from <= ?", current).
+		Order("effective_from").
+		Find(&due).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, candidate := range due {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var change models.PriceChange
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("applied_at IS NULL").
+				First(&change, "id = ?", candidate.ID).Error
+			if err != nil {
+				return err
+			}
+
+			appliedAt := now
+			change.AppliedAt = &appliedAt
+
+			var sub models.Subscription
+			err = tx.Preload("Members").Preload("Tags").First(&sub, "id = ?", change.SubscriptionID).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Подписку удалили: изменение просто помечается применённым.
+				return tx.Save(&change).Error
+			}
+			if err != nil {
+				return err
+			}
+
+			previousPrice := sub.MonthlyPrice
+			change.PreviousPrice = &previousPrice
+			if err := tx.Save(&change).Error; err != nil {
+				return err
+			}
+			if sub.MonthlyPrice == change.Price {
+				return nil
+			}
+
+			previous := sub
+			sub.MonthlyPrice = change.Price
+			if err := tx.Model(&sub).Update("monthly_price", sub.MonthlyPrice).Error; err != nil {
+				return err
+			}
+			data := models.PriceChangedData{Subscription: sub, PreviousPrice: previousPrice}
+			if err := emitEvent(tx, sub.OrgID, models.EventSubscriptionPriceChanged, sub.ID, data); err != nil {
+				return err
+			}
+			return checkBudgets(tx, sub, &previous)
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"sort"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CalculateForecast прогнозирует расходы userID на months месяцев начиная с
+// from. Учитываются даты окончания, период оплаты и ещё не применённые
+// изменения цены из changes; по разделённым подпискам — доля пользователя.
+func CalculateForecast(subscriptions []models.Subscription, changes []models.PriceChange, userID uuid.UUID, from models.YearMonth, months int) models.Forecast {
+	pending := make(map[uuid.UUID][]models.PriceChange)
+	for _, change := range changes {
+		if change.AppliedAt == nil {
+			pending[change.SubscriptionID] = append(pending[change.SubscriptionID], change)
+		}
+	}
+	for id := range pending {
+		sort.Slice(pending[id], func(i, j int) bool {
+			return pending[id][i].EffectiveFrom.Before(pending[id][j].EffectiveFrom)
+		})
+	}
+
+	forecast := models.Forecast{Months: make([]models.ForecastMonth, 0, months)}
+	for ym, i := from, 0; i < months; ym, i = ym.AddMonths(1), i+1 {
+		month := models.ForecastMonth{Month: ym}
+		for _, sub := range subscriptions {
+			if !sub.ActiveIn(ym) {
+				continue
+			}
+			sub.MonthlyPrice = priceAt(sub, pending[sub.ID], ym)
+			share := shareOf(sub, userID)
+			month.Accrued += share
+			if sub.BilledIn(ym) {
+				month.Billed += share * sub.Period()
+			}
+		}
+		forecast.Total += month.Billed
+		month.Cumulative = forecast.Total
+		forecast.Months = append(forecast.Months, month)
+	}
+	return forecast
+}
+
+// priceAt — цена подписки в месяце с учётом запланированных изменений,
+// отсортированных по месяцу.
+func priceAt(sub models.Subscription, changes []models.PriceChange, ym models.YearMonth) int {
+	price := sub.MonthlyPrice
+	for _, change := range changes {
+		if change.EffectiveFrom.After(ym) {
+			break
+		}
+		price = change.Price
+	}
+	return price
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"slices"
+	"subscribers/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCalculateForecast(t *testing.T) {
+	userID, member := uuid.New(), uuid.New()
+	jan := models.YearMonth{Year: 2025, Month: time.January}
+	feb, mar := jan.AddMonths(1), jan.AddMonths(2)
+	applied := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	monthly := models.Subscription{ID: uuid.New(), UserID: userID, MonthlyPrice: 100, StartedAt: jan}
+
+	tests := []struct {
+		name        string
+		subs        []models.Subscription
+		changes     []models.PriceChange
+		user        uuid.UUID
+		wantAccrued []int
+		wantBilled  []int
+		wantTotal   int
+	}{
+		{
+			name:        "monthly",
+			subs:        []models.Subscription{monthly},
+			user:        userID,
+			wantAccrued: []int{100, 100, 100, 100},
+			wantBilled:  []int{100, 100, 100, 100},
+			wantTotal:   400,
+		},
+		{
+			name: "quarterly billing",
+			subs: []models.Subscription{
+				{ID: uuid.New(), UserID: userID, MonthlyPrice: 100, BillingPeriod: 3, StartedAt: jan},
+			},
+			user:        userID,
+			wantAccrued: []int{100, 100, 100, 100},
+			wantBilled:  []int{300, 0, 0, 300},
+			wantTotal:   600,
+		},
+		{
+			name: "ended and not yet started",
+			subs: []models.Subscription{
+				{ID: uuid.New(), UserID: userID, MonthlyPrice: 100, StartedAt: jan, EndedAt: &feb},
+				{ID: uuid.New(), UserID: userID, MonthlyPrice: 50, StartedAt: mar},
+			},
+			user:        userID,
+			wantAccrued: []int{100, 100, 50, 50},
+			wantBilled:  []int{100, 100, 50, 50},
+			wantTotal:   300,
+		},
+		{
+			name: "pending price change",
+			subs: []models.Subscription{monthly},
+			changes: []models.PriceChange{
+				{SubscriptionID: monthly.ID, EffectiveFrom: jan.AddMonths(3), Price: 300},
+				{SubscriptionID: monthly.ID, EffectiveFrom: mar, Price: 200},
+				{SubscriptionID: monthly.ID, EffectiveFrom: feb, Price: 999, AppliedAt: &applied},
+			},
+			user:        userID,
+			wantAccrued: []int{100, 100, 200, 300},
+			wantBilled:  []int{100, 100, 200, 300},
+			wantTotal:   700,
+		},
+		{
+			name: "member share",
+			subs: []models.Subscription{
+				{
+					ID: uuid.New(), UserID: userID, MonthlyPrice: 100, StartedAt: jan,
+					SplitRule: models.SplitEqual, Members: []models.SubscriptionMember{{UserID: member}},
+				},
+			},
+			user:        member,
+			wantAccrued: []int{50, 50, 50, 50},
+			wantBilled:  []int{50, 50, 50, 50},
+			wantTotal:   200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := CalculateForecast(tt.subs, tt.changes, tt.user, jan, 4)
+
+			var accrued, billed []int
+			for i, month := range forecast.Months {
+				if want := jan.AddMonths(i); month.Month != want {
+					t.Fatalf("month %d = %s, want %s", i, month.Month, want)
+				}
+				accrued = append(accrued, month.Accrued)
+				billed = append(billed, month.Billed)
+			}
+			if !slices.Equal(accrued, tt.wantAccrued) {
+				t.Errorf("accrued = %v, want %v", accrued, tt.wantAccrued)
+			}
+			if !slices.Equal(billed, tt.wantBilled) {
+				t.Errorf("billed = %v, want %v", billed, tt.wantBilled)
+			}
+			if forecast.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", forecast.Total, tt.wantTotal)
+			}
+			if last := forecast.Months[len(forecast.Months)-1]; last.Cumulative != forecast.Total {
+				t.Errorf("cumulative in the last month = %d, want the total %d", last.Cumulative, forecast.Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxSpendingMonths = 120
+
+// CalculateMonthlySpending раскладывает расходы userID по месяцам: в каждый
+// месяц периода попадают его доли в подписках, действовавших в этом месяце.
+// Подписки должны быть загружены с участниками (GetSubscriptionsInvolvingUsers).
+// Без start период начинается с самой ранней подписки, без end — заканчивается текущим месяцем.
+func CalculateMonthlySpending(subscriptions []models.Subscription, userID uuid.UUID, startYM, endYM *models.YearMonth) ([]models.MonthlySpending, error) {
+	from, to, err := spendingPeriod(subscriptions, startYM, endYM)
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return []models.MonthlySpending{}, nil
+	}
+
+	months := make([]models.MonthlySpending, 0, to.Index()-from.Index()+1)
+	for ym := from; !ym.After(to); ym = ym.AddMonths(1) {
+		total := 0
+		for _, sub := range subscriptions {
+			if sub.ActiveIn(ym) {
+				total += shareOf(sub, userID)
+			}
+		}
+		months = append(months, models.MonthlySpending{Month: ym, Total: total})
+	}
+	return months, nil
+}
+
+// spendingPeriod определяет границы помесячной раскладки. Пустой период
+// возвращается как to раньше from.
+func spendingPeriod(subscriptions []models.Subscription, startYM, endYM *models.YearMonth) (from, to models.YearMonth, err error) {
+	if len(subscriptions) == 0 && startYM == nil {
+		return models.YearMonth{Year: 1}, models.YearMonth{}, nil
+	}
+
+	if startYM != nil {
+		from = *startYM
+	} else {
+		from = subscriptions[0].StartedAt
+		for _, sub := range subscriptions[1:] {
+			if sub.StartedAt.Before(from) {
+				from = sub.StartedAt
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	to = models.YearMonth{Year: now.Year(), Month: now.Month()}
+	if endYM != nil {
+		to = *endYM
+	}
+
+	if !to.Before(from) && to.Index()-from.Index() >= maxSpendingMonths {
+		return from, to, fmt.Errorf("%w: at most %d months are allowed", ErrPeriodTooLong, maxSpendingMonths)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CalculateOrganizationTotals считает стоимость подписок организации с
+// разбивкой по участникам и сервисам. Период фильтруется так же, как в
+// CalculateSubscriptionsTotal: по месяцу начала подписки.
+func CalculateOrganizationTotals(db *gorm.DB, tenant models.Tenant, startYM, endYM *models.YearMonth) (_ *models.OrganizationTotals, err error) {
+	db, span := startSpan(db, "CalculateOrganizationTotals")
+	defer func() { endSpan(span, err) }()
+
+	if err := requireAdmin(tenant); err != nil {
+		return nil, err
+	}
+
+	query := func() *gorm.DB {
+		q := database.ReadReplica(db).Model(&models.Subscription{}).Scopes(tenantScope(tenant))
+		if startYM != nil {
+			q = q.Where("started_at >= ?", *startYM)
+		}
+		if endYM != nil {
+			q = q.Where("started_at <= ?", *endYM)
+		}
+		return q
+	}
+
+	totals := &models.OrganizationTotals{
+		ByUser:    []models.UserSpending{},
+		ByService: []models.ServiceSpending{},
+	}
+	err = query().
+		Select("user_id, COALESCE(SUM(monthly_price), 0) AS total").
+		Group("user_id").Order("total DESC, user_id").
+		Scan(&totals.ByUser).Error
+	if err != nil {
+		return nil, err
+	}
+	err = query().
+		Select("service_name, COALESCE(SUM(monthly_price), 0) AS total").
+		Group("service_name").Order("service_name").
+		Scan(&totals.ByService).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spending := range totals.ByUser {
+		totals.Total += spending.Total
+	}
+	return totals, nil
+}
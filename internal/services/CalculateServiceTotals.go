@@ -0,0 +1,33 @@
+package services
+
+import (
+	"sort"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CalculateServiceTotals группирует доли userID в уже загруженных подписках по
+// сервису. Фильтр по периоду и доли те же, что и в CalculateSubscriptionsTotal:
+// учитываются подписки, начавшиеся в пределах [startYM, endYM].
+func CalculateServiceTotals(subscriptions []models.Subscription, userID uuid.UUID, startYM, endYM *models.YearMonth) []models.ServiceSpending {
+	totals := make(map[string]int)
+	for _, sub := range subscriptions {
+		if startYM != nil && sub.StartedAt.Before(*startYM) {
+			continue
+		}
+		if endYM != nil && sub.StartedAt.After(*endYM) {
+			continue
+		}
+		totals[sub.ServiceName] += shareOf(sub, userID)
+	}
+
+	result := make([]models.ServiceSpending, 0, len(totals))
+	for name, total := range totals {
+		result = append(result, models.ServiceSpending{ServiceName: name, Total: total})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ServiceName < result[j].ServiceName
+	})
+	return result
+}
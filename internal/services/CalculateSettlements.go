@@ -0,0 +1,65 @@
+package services
+
+import (
+	"bytes"
+	"sort"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CalculateSettlements показывает, кто кому должен в каждом месяце периода
+// по разделённым подпискам. Долги между парой пользователей взаимно
+// зачитываются; в результат попадают только долги с участием userID.
+// Границы периода — как у CalculateMonthlySpending.
+func CalculateSettlements(subscriptions []models.Subscription, userID uuid.UUID, startYM, endYM *models.YearMonth) ([]models.MonthlySettlement, error) {
+	from, to, err := spendingPeriod(subscriptions, startYM, endYM)
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return []models.MonthlySettlement{}, nil
+	}
+
+	months := make([]models.MonthlySettlement, 0, to.Index()-from.Index()+1)
+	for ym := from; !ym.After(to); ym = ym.AddMonths(1) {
+		// Долг храним по упорядоченной паре: положительный — first должен second.
+		balances := make(map[[2]uuid.UUID]int)
+		for _, sub := range subscriptions {
+			if !sub.ActiveIn(ym) {
+				continue
+			}
+			for _, share := range CalculateShares(sub)[1:] {
+				if share.Amount == 0 {
+					continue
+				}
+				if bytes.Compare(share.UserID[:], sub.UserID[:]) < 0 {
+					balances[[2]uuid.UUID{share.UserID, sub.UserID}] += share.Amount
+				} else {
+					balances[[2]uuid.UUID{sub.UserID, share.UserID}] -= share.Amount
+				}
+			}
+		}
+
+		debts := []models.Debt{}
+		for pair, amount := range balances {
+			if pair[0] != userID && pair[1] != userID {
+				continue
+			}
+			switch {
+			case amount > 0:
+				debts = append(debts, models.Debt{From: pair[0], To: pair[1], Amount: amount})
+			case amount < 0:
+				debts = append(debts, models.Debt{From: pair[1], To: pair[0], Amount: -amount})
+			}
+		}
+		sort.Slice(debts, func(i, j int) bool {
+			if debts[i].From != debts[j].From {
+				return debts[i].From.String() < debts[j].From.String()
+			}
+			return debts[i].To.String() < debts[j].To.String()
+		})
+		months = append(months, models.MonthlySettlement{Month: ym, Debts: debts})
+	}
+	return months, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CalculateShares делит ежемесячную стоимость подписки по её правилу.
+// Первая доля — плательщика: ему достаётся остаток после долей участников,
+// в том числе копейки от округления. Без правила вся стоимость на плательщике.
+func CalculateShares(sub models.Subscription) []models.Share {
+	shares := make([]models.Share, 0, len(sub.Members)+1)
+	shares = append(shares, models.Share{UserID: sub.UserID})
+	if sub.SplitRule == "" || len(sub.Members) == 0 {
+		shares[0].Amount = sub.MonthlyPrice
+		return shares
+	}
+
+	rest := sub.MonthlyPrice
+	for _, member := range sub.Members {
+		var amount int
+		switch sub.SplitRule {
+		case models.SplitEqual:
+			amount = sub.MonthlyPrice / (len(sub.Members) + 1)
+		case models.SplitPercent:
+			amount = sub.MonthlyPrice * member.Percent / 100
+		case models.SplitFixed:
+			amount = min(member.Amount, rest)
+		}
+		rest -= amount
+		shares = append(shares, models.Share{UserID: member.UserID, Amount: amount})
+	}
+	shares[0].Amount = rest
+	return shares
+}
+
+// shareOf — доля userID в стоимости подписки; 0, если он в ней не участвует.
+func shareOf(sub models.Subscription, userID uuid.UUID) int {
+	total := 0
+	for _, share := range CalculateShares(sub) {
+		if share.UserID == userID {
+			total += share.Amount
+		}
+	}
+	return total
+}
+
+// validateSplit проверяет, что доли участников помещаются в стоимость подписки.
+func validateSplit(rule string, price int, payerID uuid.UUID, members []models.SubscriptionMember) error {
+	seen := make(map[uuid.UUID]bool, len(members))
+	percent, amount := 0, 0
+	for _, member := range members {
+		if member.UserID == payerID {
+			return errors.New("the payer cannot be a member of their own subscription")
+		}
+		if seen[member.UserID] {
+			return fmt.Errorf("duplicate member %s", member.UserID)
+		}
+		seen[member.UserID] = true
+		percent += member.Percent
+		amount += member.Amount
+	}
+
+	switch rule {
+	case models.SplitEqual:
+	case models.SplitPercent:
+		if percent > 100 {
+			return fmt.Errorf("member percentages add up to %d%%, at most 100%% is allowed", percent)
+		}
+	case models.SplitFixed:
+		if amount > price {
+			return fmt.Errorf("member amounts add up to %d, more than the monthly price %d", amount, price)
+		}
+	default:
+		return fmt.Errorf("unknown split rule %q", rule)
+	}
+	return nil
+}
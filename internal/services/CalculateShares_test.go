@@ -0,0 +1,131 @@
+package services
+
+import (
+	"subscribers/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCalculateShares(t *testing.T) {
+	payer, alice, bob := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		rule    string
+		price   int
+		members []models.SubscriptionMember
+		want    []int
+	}{
+		{
+			name:  "not shared",
+			price: 1000,
+			want:  []int{1000},
+		},
+		{
+			name:  "rule without members",
+			rule:  models.SplitEqual,
+			price: 1000,
+			want:  []int{1000},
+		},
+		{
+			name:    "equal, payer gets the rounding remainder",
+			rule:    models.SplitEqual,
+			price:   1000,
+			members: []models.SubscriptionMember{{UserID: alice}, {UserID: bob}},
+			want:    []int{334, 333, 333},
+		},
+		{
+			name:    "percent",
+			rule:    models.SplitPercent,
+			price:   999,
+			members: []models.SubscriptionMember{{UserID: alice, Percent: 25}, {UserID: bob, Percent: 50}},
+			want:    []int{251, 249, 499},
+		},
+		{
+			name:    "fixed",
+			rule:    models.SplitFixed,
+			price:   1000,
+			members: []models.SubscriptionMember{{UserID: alice, Amount: 300}, {UserID: bob, Amount: 200}},
+			want:    []int{500, 300, 200},
+		},
+		{
+			name:    "fixed amounts above a lowered price",
+			rule:    models.SplitFixed,
+			price:   400,
+			members: []models.SubscriptionMember{{UserID: alice, Amount: 300}, {UserID: bob, Amount: 200}},
+			want:    []int{0, 300, 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := models.Subscription{UserID: payer, MonthlyPrice: tt.price, SplitRule: tt.rule, Members: tt.members}
+			shares := CalculateShares(sub)
+			if len(shares) != len(tt.want) {
+				t.Fatalf("got %d shares, want %d", len(shares), len(tt.want))
+			}
+
+			sum := 0
+			for i, share := range shares {
+				if share.Amount != tt.want[i] {
+					t.Errorf("share %d = %d, want %d", i, share.Amount, tt.want[i])
+				}
+				sum += share.Amount
+			}
+			if sum != tt.price {
+				t.Errorf("shares add up to %d, want the price %d", sum, tt.price)
+			}
+			if shares[0].UserID != payer {
+				t.Errorf("first share belongs to %s, want the payer", shares[0].UserID)
+			}
+		})
+	}
+}
+
+func TestShareOf(t *testing.T) {
+	payer, member := uuid.New(), uuid.New()
+	sub := models.Subscription{
+		UserID:       payer,
+		MonthlyPrice: 900,
+		SplitRule:    models.SplitEqual,
+		Members:      []models.SubscriptionMember{{UserID: member}, {UserID: uuid.New()}},
+	}
+
+	if got := shareOf(sub, payer); got != 300 {
+		t.Errorf("payer share = %d, want 300", got)
+	}
+	if got := shareOf(sub, member); got != 300 {
+		t.Errorf("member share = %d, want 300", got)
+	}
+	if got := shareOf(sub, uuid.New()); got != 0 {
+		t.Errorf("outsider share = %d, want 0", got)
+	}
+}
+
+func TestValidateSplit(t *testing.T) {
+	payer, alice, bob := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		rule    string
+		members []models.SubscriptionMember
+		wantErr bool
+	}{
+		{"equal", models.SplitEqual, []models.SubscriptionMember{{UserID: alice}, {UserID: bob}}, false},
+		{"percent up to 100", models.SplitPercent, []models.SubscriptionMember{{UserID: alice, Percent: 40}, {UserID: bob, Percent: 60}}, false},
+		{"percent above 100", models.SplitPercent, []models.SubscriptionMember{{UserID: alice, Percent: 60}, {UserID: bob, Percent: 41}}, true},
+		{"fixed up to the price", models.SplitFixed, []models.SubscriptionMember{{UserID: alice, Amount: 600}, {UserID: bob, Amount: 400}}, false},
+		{"fixed above the price", models.SplitFixed, []models.SubscriptionMember{{UserID: alice, Amount: 600}, {UserID: bob, Amount: 401}}, true},
+		{"payer as member", models.SplitEqual, []models.SubscriptionMember{{UserID: payer}}, true},
+		{"duplicate member", models.SplitEqual, []models.SubscriptionMember{{UserID: alice}, {UserID: alice}}, true},
+		{"unknown rule", "weighted", []models.SubscriptionMember{{UserID: alice}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSplit(tt.rule, 1000, payer, tt.members)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateSplit() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
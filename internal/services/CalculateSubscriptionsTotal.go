@@ -1,43 +1,101 @@
 package services
 
 import (
+	"fmt"
+	"sort"
+	database "subscribers/internal/db"
 	"subscribers/internal/models"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
-func CalculateSubscriptionsTotal(db *gorm.DB, userID uuid.UUID, serviceName string, startYM, endYM *models.YearMonth) (int, error) {
-	var subscriptions []models.Subscription
+func CalculateSubscriptionsTotal(db *gorm.DB, tenant models.Tenant, userID uuid.UUID, filter models.SubscriptionFilter) (int, error) {
+	breakdown, err := CalculateSpendingBreakdown(db, tenant, userID, filter, "")
+	if err != nil {
+		return 0, err
+	}
+	return breakdown.Total, nil
+}
 
-	query := db.Where("user_id = ?", userID)
+// CalculateSpendingBreakdown считает итог по подпискам пользователя и, если
+// задан groupBy, суммы по сервисам, категориям или тегам.
+func CalculateSpendingBreakdown(db *gorm.DB, tenant models.Tenant, userID uuid.UUID, filter models.SubscriptionFilter, groupBy string) (_ *models.SpendingBreakdown, err error) {
+	db, span := startSpan(db, "CalculateSpendingBreakdown")
+	defer func() { endSpan(span, err) }()
+	span.SetAttributes(
+		attribute.String("user.id", userID.String()),
+		attribute.String("service.name", filter.ServiceName),
+		attribute.String("group_by", groupBy),
+	)
 
-	if serviceName != "" {
-		query = query.Where("service_name = ?", serviceName)
+	if err := authorizeRead(tenant, userID); err != nil {
+		return nil, err
 	}
 
-	if startYM != nil {
-		query = query.Where(
-			"(EXTRACT(YEAR FROM started_at) * 100 + EXTRACT(MONTH FROM started_at)) >= ?",
-			startYM.Year*100+int(startYM.Month),
-		)
-	}
+	var subscriptions []models.Subscription
 
-	if endYM != nil {
-		query = query.Where(
-			"(EXTRACT(YEAR FROM started_at) * 100 + EXTRACT(MONTH FROM started_at)) <= ?",
-			endYM.Year*100+int(endYM.Month),
-		)
+	// Разделённые подписки учитываются у каждого участника его долей.
+	query := database.ReadReplica(db).
+		Scopes(orgScope(tenant), involving(userID), subscriptionFilter(filter)).
+		Preload("Members")
+	if groupBy == models.GroupByTag {
+		query = query.Preload("Tags")
 	}
 
 	if err := query.Find(&subscriptions).Error; err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("subscriptions.count", len(subscriptions)))
+
+	breakdown := &models.SpendingBreakdown{GroupBy: groupBy}
+	groups := make(map[string]*models.GroupSpending)
+	add := func(key string, amount int) {
+		group, ok := groups[key]
+		if !ok {
+			group = &models.GroupSpending{Key: key}
+			groups[key] = group
+		}
+		group.Total += amount
+		group.Subscriptions++
 	}
 
-	total := 0
 	for _, sub := range subscriptions {
-		total += sub.MonthlyPrice
+		share := shareOf(sub, userID)
+		breakdown.Total += share
+
+		switch groupBy {
+		case "":
+		case models.GroupByService:
+			add(sub.ServiceName, share)
+		case models.GroupByCategory:
+			add(sub.Category, share)
+		case models.GroupByTag:
+			if len(sub.Tags) == 0 {
+				add("", share)
+			}
+			for _, tag := range sub.Tags {
+				add(tag.Tag, share)
+			}
+		default:
+			return nil, fmt.Errorf("unknown group_by %q", groupBy)
+		}
+	}
+
+	if groupBy != "" {
+		breakdown.Groups = make([]models.GroupSpending, 0, len(groups))
+		for _, group := range groups {
+			breakdown.Groups = append(breakdown.Groups, *group)
+		}
+		sort.Slice(breakdown.Groups, func(i, j int) bool {
+			if breakdown.Groups[i].Total != breakdown.Groups[j].Total {
+				return breakdown.Groups[i].Total > breakdown.Groups[j].Total
+			}
+			return breakdown.Groups[i].Key < breakdown.Groups[j].Key
+		})
 	}
 
-	return total, nil
+	return breakdown, nil
 }
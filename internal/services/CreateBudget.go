@@ -0,0 +1,52 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func CreateBudget(db *gorm.DB, tenant models.Tenant, req models.BudgetRequest) (_ *models.Budget, err error) {
+	db, span := startSpan(db, "CreateBudget")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid UUID: %w", err)
+	}
+	if err := authorizeUser(db, tenant, userID); err != nil {
+		return nil, err
+	}
+
+	target, err := budgetTarget(db, req.Scope, req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := models.Budget{
+		ID:     uuid.New(),
+		UserID: userID,
+		OrgID:  orgIDOf(tenant),
+		Scope:  req.Scope,
+		Target: target,
+		Amount: req.Amount,
+	}
+
+	var count int64
+	err = db.Model(&models.Budget{}).Scopes(orgScope(tenant)).
+		Where("user_id = ? AND scope = ? AND target = ?", userID, budget.Scope, budget.Target).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrBudgetExists
+	}
+
+	if err := db.Create(&budget).Error; err != nil {
+		return nil, fmt.Errorf("error saving the budget: %w", err)
+	}
+	return &budget, nil
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func CreateCatalogService(db *gorm.DB, req models.CatalogServiceRequest) (_ *models.CatalogService, err error) {
+	db, span := startSpan(db, "CreateCatalogService")
+	defer func() { endSpan(span, err) }()
+
+	service := models.CatalogService{ID: uuid.New()}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return saveCatalogService(tx, &service, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateOrganization создаёт организацию; её создатель становится администратором.
+func CreateOrganization(db *gorm.DB, ownerID uuid.UUID, req models.CreateOrganizationRequest) (_ *models.Organization, err error) {
+	db, span := startSpan(db, "CreateOrganization")
+	defer func() { endSpan(span, err) }()
+
+	org := models.Organization{ID: uuid.New(), Name: req.Name}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&org).Error; err != nil {
+			return fmt.Errorf("error saving the organization: %w", err)
+		}
+		owner := models.Membership{OrgID: org.ID, UserID: ownerID, Role: models.RoleAdmin}
+		if err := tx.Create(&owner).Error; err != nil {
+			return fmt.Errorf("error saving the organization owner: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
@@ -9,12 +9,19 @@ import (
 	"gorm.io/gorm"
 )
 
-func CreateSubscription(db *gorm.DB, req models.CreateSubscriptionRequest) (uuid.UUID, error) {
+func CreateSubscription(db *gorm.DB, tenant models.Tenant, req models.CreateSubscriptionRequest) (_ uuid.UUID, err error) {
+	db, span := startSpan(db, "CreateSubscription")
+	defer func() { endSpan(span, err) }()
+
 	userID, err := uuid.Parse(req.UserID)
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("Invalid UUID: %w", err)
 	}
 
+	if err := authorizeUser(db, tenant, userID); err != nil {
+		return uuid.Nil, err
+	}
+
 	startYM, err := utils.ParseYearMonth(req.StartDate)
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("Error parsing start_date: %w", err)
@@ -26,28 +33,70 @@ func CreateSubscription(db *gorm.DB, req models.CreateSubscriptionRequest) (uuid
 		if err != nil {
 			return uuid.Nil, fmt.Errorf("Error parsing end_date: %w", err)
 		}
+		if ym.Before(startYM) {
+			return uuid.Nil, fmt.Errorf("%w: end_date is before start_date", ErrInvalidDate)
+		}
 		endYM = &ym
 	}
 
-	exists, err := utils.SubscriptionExists(db, userID, req.ServiceName)
+	service, err := ResolveServiceName(db, req.ServiceName)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("Service catalog lookup error: %w", err)
+	}
+	serviceName, serviceID, price, category := req.ServiceName, (*uuid.UUID)(nil), req.Price, req.Category
+	if service != nil {
+		serviceName, serviceID = service.Name, &service.ID
+		if category == "" {
+			category = service.Category
+		}
+	}
+	if req.Plan != "" {
+		var plan models.ServicePlan
+		ok := false
+		if service != nil {
+			plan, ok = service.Plan(req.Plan)
+		}
+		if !ok {
+			return uuid.Nil, fmt.Errorf("%w %q for service %q", ErrUnknownPlan, req.Plan, req.ServiceName)
+		}
+		if price == 0 {
+			price = plan.Price
+		}
+	}
+
+	exists, err := utils.SubscriptionExists(db.Scopes(tenantScope(tenant)), userID, serviceName)
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("Record search error: %w", err)
 	}
 	if exists {
-		return uuid.Nil, fmt.Errorf("A subscription already exists for this user and the service.")
+		return uuid.Nil, ErrSubscriptionExists
 	}
 
 	sub := models.Subscription{
-		ID:           uuid.New(),
-		ServiceName:  req.ServiceName,
-		MonthlyPrice: req.Price,
-		UserID:       userID,
-		StartedAt:    startYM,
-		EndedAt:      endYM,
+		ID:            uuid.New(),
+		ServiceName:   serviceName,
+		ServiceID:     serviceID,
+		MonthlyPrice:  price,
+		BillingPeriod: req.BillingPeriod,
+		Category:      category,
+		UserID:        userID,
+		OrgID:         orgIDOf(tenant),
+		StartedAt:     startYM,
+		EndedAt:       endYM,
 	}
+	sub.Tags = subscriptionTags(sub, req.Tags)
 
-	if err := db.Create(&sub).Error; err != nil {
-		return uuid.Nil, fmt.Errorf("error saving the subscription: %w", err)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&sub).Error; err != nil {
+			return fmt.Errorf("error saving the subscription: %w", err)
+		}
+		if err := emitEvent(tx, sub.OrgID, models.EventSubscriptionCreated, sub.ID, sub); err != nil {
+			return err
+		}
+		return checkBudgets(tx, sub, nil)
+	})
+	if err != nil {
+		return uuid.Nil, err
 	}
 
 	return sub.ID, nil
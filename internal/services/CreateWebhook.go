@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func CreateWebhook(db *gorm.DB, tenant models.Tenant, req models.CreateWebhookRequest) (_ *models.WebhookEndpoint, err error) {
+	db, span := startSpan(db, "CreateWebhook")
+	defer func() { endSpan(span, err) }()
+
+	if err := authorizeWebhooks(tenant); err != nil {
+		return nil, err
+	}
+
+	for _, event := range req.Events {
+		if !isKnownWebhookEvent(event) {
+			return nil, fmt.Errorf("unknown webhook event: %s", event)
+		}
+	}
+
+	endpoint := models.WebhookEndpoint{
+		ID:     uuid.New(),
+		URL:    req.URL,
+		OrgID:  orgIDOf(tenant),
+		Secret: req.Secret,
+		Events: req.Events,
+	}
+
+	if err := db.Create(&endpoint).Error; err != nil {
+		return nil, fmt.Errorf("error saving the webhook endpoint: %w", err)
+	}
+
+	return &endpoint, nil
+}
+
+func isKnownWebhookEvent(event string) bool {
+	if event == "*" {
+		return true
+	}
+	for _, known := range models.WebhookEvents {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
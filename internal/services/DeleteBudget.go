@@ -0,0 +1,22 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func DeleteBudget(db *gorm.DB, tenant models.Tenant, id uuid.UUID) (err error) {
+	db, span := startSpan(db, "DeleteBudget")
+	defer func() { endSpan(span, err) }()
+
+	result := db.Scopes(tenantScope(tenant)).Delete(&models.Budget{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
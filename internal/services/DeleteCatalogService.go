@@ -0,0 +1,34 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeleteCatalogService удаляет запись каталога. Подписки сохраняют название
+// и становятся подписками вне каталога.
+func DeleteCatalogService(db *gorm.DB, id uuid.UUID) (err error) {
+	db, span := startSpan(db, "DeleteCatalogService")
+	defer func() { endSpan(span, err) }()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.CatalogService{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Delete(&models.ServiceAlias{}, "service_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.ServicePlan{}, "service_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Subscription{}).
+			Where("service_id = ?", id).
+			Update("service_id", nil).Error
+	})
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeletePriceChange отменяет ещё не применённое изменение цены.
+func DeletePriceChange(db *gorm.DB, tenant models.Tenant, subID, changeID uuid.UUID) (err error) {
+	db, span := startSpan(db, "DeletePriceChange")
+	defer func() { endSpan(span, err) }()
+
+	var sub models.Subscription
+	if err := db.Scopes(tenantScope(tenant)).Select("id").First(&sub, "id = ?", subID).Error; err != nil {
+		return err
+	}
+
+	result := db.Where("id = ? AND subscription_id = ? AND applied_at IS NULL", changeID, subID).
+		Delete(&models.PriceChange{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
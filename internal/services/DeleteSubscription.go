@@ -7,13 +7,23 @@ import (
 	"gorm.io/gorm"
 )
 
-func DeleteSubscription(db *gorm.DB, id uuid.UUID) error {
-	result := db.Delete(&models.Subscription{}, "id = ?", id)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-	return nil
+func DeleteSubscription(db *gorm.DB, tenant models.Tenant, id uuid.UUID) (err error) {
+	db, span := startSpan(db, "DeleteSubscription")
+	defer func() { endSpan(span, err) }()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var sub models.Subscription
+		if err := tx.Scopes(tenantScope(tenant)).First(&sub, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Subscription{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return emitEvent(tx, sub.OrgID, models.EventSubscriptionDeleted, sub.ID, sub)
+	})
 }
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func DeleteWebhook(db *gorm.DB, tenant models.Tenant, id uuid.UUID) (err error) {
+	db, span := startSpan(db, "DeleteWebhook")
+	defer func() { endSpan(span, err) }()
+
+	if err := authorizeWebhooks(tenant); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(orgScope(tenant)).Delete(&models.WebhookEndpoint{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Delete(&models.WebhookDelivery{}, "endpoint_id = ?", id).Error
+	})
+}
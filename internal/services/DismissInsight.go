@@ -0,0 +1,27 @@
+package services
+
+import (
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DismissInsight скрывает находку; анализ не создаёт её повторно.
+func DismissInsight(db *gorm.DB, tenant models.Tenant, id uuid.UUID) (err error) {
+	db, span := startSpan(db, "DismissInsight")
+	defer func() { endSpan(span, err) }()
+
+	result := db.Model(&models.Insight{}).Scopes(tenantScope(tenant)).
+		Where("id = ? AND dismissed_at IS NULL", id).
+		Update("dismissed_at", time.Now().UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var insight models.Insight
+		return db.Scopes(tenantScope(tenant)).Select("id").First(&insight, "id = ?", id).Error
+	}
+	return nil
+}
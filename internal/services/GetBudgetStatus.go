@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// GetBudgetStatus сравнивает бюджеты пользователя с прогнозом расходов по
+// действующим подпискам. Без startYM период начинается с текущего месяца,
+// без endYM — им и заканчивается.
+func GetBudgetStatus(db *gorm.DB, tenant models.Tenant, userID uuid.UUID, startYM, endYM *models.YearMonth) (_ []models.BudgetStatus, err error) {
+	db, span := startSpan(db, "GetBudgetStatus")
+	defer func() { endSpan(span, err) }()
+	span.SetAttributes(attribute.String("user.id", userID.String()))
+
+	if err := authorizeRead(tenant, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	from := models.YearMonth{Year: now.Year(), Month: now.Month()}
+	if startYM != nil {
+		from = *startYM
+	}
+	to := from
+	if endYM != nil {
+		to = *endYM
+	}
+	if to.Index()-from.Index() >= maxSpendingMonths {
+		return nil, fmt.Errorf("%w: at most %d months are allowed", ErrPeriodTooLong, maxSpendingMonths)
+	}
+
+	replica := database.ReadReplica(db)
+
+	var budgets []models.Budget
+	err = replica.Scopes(orgScope(tenant)).Where("user_id = ?", userID).Order("created_at").Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(budgets) == 0 {
+		return []models.BudgetStatus{}, nil
+	}
+
+	var subscriptions []models.Subscription
+	err = replica.Scopes(orgScope(tenant), involving(userID)).Preload("Members").Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		statuses = append(statuses, CalculateBudgetStatus(budget, subscriptions, userID, from, to))
+	}
+	return statuses, nil
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func GetBudgets(db *gorm.DB, tenant models.Tenant, userID uuid.UUID) (_ []models.Budget, err error) {
+	db, span := startSpan(db, "GetBudgets")
+	defer func() { endSpan(span, err) }()
+
+	if err := authorizeRead(tenant, userID); err != nil {
+		return nil, err
+	}
+
+	var budgets []models.Budget
+	err = database.ReadReplica(db).Scopes(orgScope(tenant)).
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
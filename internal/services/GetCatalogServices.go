@@ -0,0 +1,36 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetCatalogServices возвращает каталог, с category — только эту категорию.
+func GetCatalogServices(db *gorm.DB, category string) (_ []models.CatalogService, err error) {
+	db, span := startSpan(db, "GetCatalogServices")
+	defer func() { endSpan(span, err) }()
+
+	query := db.Preload("Aliases").Preload("Plans").Order("name")
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	var catalog []models.CatalogService
+	if err := query.Find(&catalog).Error; err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func GetCatalogService(db *gorm.DB, id uuid.UUID) (_ *models.CatalogService, err error) {
+	db, span := startSpan(db, "GetCatalogService")
+	defer func() { endSpan(span, err) }()
+
+	var service models.CatalogService
+	if err := db.Preload("Aliases").Preload("Plans").First(&service, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func GetFailedWebhookDeliveries(db *gorm.DB, tenant models.Tenant) (_ []models.WebhookDelivery, err error) {
+	db, span := startSpan(db, "GetFailedWebhookDeliveries")
+	defer func() { endSpan(span, err) }()
+
+	if err := authorizeWebhooks(tenant); err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	err = db.Where("status = ?", models.DeliveryStatusFailed).
+		Where("endpoint_id IN (?)", tenantEndpoints(db, tenant)).
+		Order("updated_at DESC").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// GetForecast — прогноз расходов пользователя на months месяцев. Без startYM
+// прогноз начинается со следующего месяца.
+func GetForecast(db *gorm.DB, tenant models.Tenant, userID uuid.UUID, startYM *models.YearMonth, months int) (_ *models.Forecast, err error) {
+	db, span := startSpan(db, "GetForecast")
+	defer func() { endSpan(span, err) }()
+	span.SetAttributes(
+		attribute.String("user.id", userID.String()),
+		attribute.Int("forecast.months", months),
+	)
+
+	if err := authorizeRead(tenant, userID); err != nil {
+		return nil, err
+	}
+	if months > maxSpendingMonths {
+		return nil, fmt.Errorf("%w: at most %d months are allowed", ErrPeriodTooLong, maxSpendingMonths)
+	}
+
+	now := time.Now().UTC()
+	from := models.YearMonth{Year: now.Year(), Month: now.Month()}.AddMonths(1)
+	if startYM != nil {
+		from = *startYM
+	}
+	to := from.AddMonths(months - 1)
+
+	replica := database.ReadReplica(db)
+
+	// Подписки, закончившиеся до начала прогноза, не нужны.
+	var subscriptions []models.Subscription
+	err = replica.Scopes(orgScope(tenant), involving(userID)).
+		Where("started_at <= ? AND (ended_at IS NULL OR ended_at >= ?)", to, from).
+		Preload("Members").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		ids = append(ids, sub.ID)
+	}
+	var changes []models.PriceChange
+	if len(ids) > 0 {
+		err = replica.Where("subscription_id IN ? AND applied_at IS NULL", ids).Find(&changes).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	forecast := CalculateForecast(subscriptions, changes, userID, from, months)
+	return &forecast, nil
+}
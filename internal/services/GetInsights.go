@@ -0,0 +1,34 @@
+package services
+
+import (
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetInsights возвращает находки пользователя, новые первыми. kind — вид
+// находок, пусто — все; скрытые возвращаются только с includeDismissed.
+func GetInsights(db *gorm.DB, tenant models.Tenant, userID uuid.UUID, kind string, includeDismissed bool) (_ []models.Insight, err error) {
+	db, span := startSpan(db, "GetInsights")
+	defer func() { endSpan(span, err) }()
+
+	if err := authorizeRead(tenant, userID); err != nil {
+		return nil, err
+	}
+
+	query := database.ReadReplica(db).Scopes(orgScope(tenant)).Where("user_id = ?", userID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if !includeDismissed {
+		query = query.Where("dismissed_at IS NULL")
+	}
+
+	var insights []models.Insight
+	if err := query.Order("created_at DESC").Find(&insights).Error; err != nil {
+		return nil, err
+	}
+	return insights, nil
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func GetMembers(db *gorm.DB, tenant models.Tenant) (_ []models.Membership, err error) {
+	db, span := startSpan(db, "GetMembers")
+	defer func() { endSpan(span, err) }()
+
+	if tenant.Personal() {
+		return nil, ErrForbidden
+	}
+
+	var members []models.Membership
+	if err := db.Where("org_id = ?", tenant.OrgID).Order("created_at, user_id").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetPriceChanges возвращает запланированные и уже применённые изменения цены подписки.
+func GetPriceChanges(db *gorm.DB, tenant models.Tenant, subID uuid.UUID) (_ []models.PriceChange, err error) {
+	db, span := startSpan(db, "GetPriceChanges")
+	defer func() { endSpan(span, err) }()
+
+	var sub models.Subscription
+	if err := db.Scopes(tenantScope(tenant)).Select("id").First(&sub, "id = ?", subID).Error; err != nil {
+		return nil, err
+	}
+
+	var changes []models.PriceChange
+	if err := db.Where("subscription_id = ?", subID).Order("effective_from, created_at").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
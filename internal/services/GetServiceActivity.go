@@ -0,0 +1,28 @@
+package services
+
+import (
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetServiceActivity считает по всем пользователям подписки, действующие в месяце ym,
+// и их суммарную стоимость в разрезе сервисов. Это служебная статистика для
+// метрик, поэтому она не ограничена арендатором.
+func GetServiceActivity(db *gorm.DB, ym models.YearMonth) (_ []models.ServiceActivity, err error) {
+	db, span := startSpan(db, "GetServiceActivity")
+	defer func() { endSpan(span, err) }()
+
+	var activity []models.ServiceActivity
+	err = database.ReadReplica(db).Model(&models.Subscription{}).
+		Select("service_name, COUNT(*) AS subscriptions, COALESCE(SUM(monthly_price), 0) AS monthly_spend").
+		Where("started_at <= ? AND (ended_at IS NULL OR ended_at >= ?)", ym, ym).
+		Group("service_name").
+		Order("service_name").
+		Scan(&activity).Error
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
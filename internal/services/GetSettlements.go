@@ -0,0 +1,35 @@
+package services
+
+import (
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// GetSettlements — помесячные взаиморасчёты userID по подпискам, которые
+// он оплачивает или делит с другими.
+func GetSettlements(db *gorm.DB, tenant models.Tenant, userID uuid.UUID, startYM, endYM *models.YearMonth) (_ []models.MonthlySettlement, err error) {
+	db, span := startSpan(db, "GetSettlements")
+	defer func() { endSpan(span, err) }()
+	span.SetAttributes(attribute.String("user.id", userID.String()))
+
+	if err := authorizeRead(tenant, userID); err != nil {
+		return nil, err
+	}
+
+	var subscriptions []models.Subscription
+	err = database.ReadReplica(db).
+		Scopes(orgScope(tenant), involving(userID)).
+		Where("split_rule <> ''").
+		Preload("Members").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("subscriptions.count", len(subscriptions)))
+
+	return CalculateSettlements(subscriptions, userID, startYM, endYM)
+}
@@ -7,9 +7,12 @@ import (
 	"gorm.io/gorm"
 )
 
-func GetSubscriptionByID(db *gorm.DB, id uuid.UUID) (*models.Subscription, error) {
+func GetSubscriptionByID(db *gorm.DB, tenant models.Tenant, id uuid.UUID) (_ *models.Subscription, err error) {
+	db, span := startSpan(db, "GetSubscriptionByID")
+	defer func() { endSpan(span, err) }()
+
 	var sub models.Subscription
-	err := db.First(&sub, "id = ?", id).Error
+	err = db.Scopes(tenantScope(tenant)).Preload("Members").Preload("Tags").First(&sub, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -1,15 +1,36 @@
 package services
 
 import (
+	database "subscribers/internal/db"
 	"subscribers/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-func GetSubscriptions(db *gorm.DB, userID uuid.UUID) ([]models.Subscription, error) {
+func GetSubscriptions(db *gorm.DB, tenant models.Tenant, userID uuid.UUID, filter models.SubscriptionFilter, page models.Pagination) (_ []models.Subscription, err error) {
+	db, span := startSpan(db, "GetSubscriptions")
+	defer func() { endSpan(span, err) }()
+
+	if err := authorizeRead(tenant, userID); err != nil {
+		return nil, err
+	}
+
 	var subscriptions []models.Subscription
-	if err := db.Where("user_id = ?", userID).Find(&subscriptions).Error; err != nil {
+
+	query := database.ReadReplica(db).
+		Scopes(tenantScope(tenant), subscriptionFilter(filter)).
+		Where("user_id = ?", userID).
+		Preload("Tags").
+		Order("started_at, id")
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit)
+	}
+	if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+
+	if err := query.Find(&subscriptions).Error; err != nil {
 		return nil, err
 	}
 	return subscriptions, nil
@@ -0,0 +1,34 @@
+package services
+
+import (
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetSubscriptionsByUsers загружает подписки нескольких пользователей одним запросом.
+// Подписки, недоступные арендатору, в результат не попадают.
+func GetSubscriptionsByUsers(db *gorm.DB, tenant models.Tenant, userIDs []uuid.UUID) (_ map[uuid.UUID][]models.Subscription, err error) {
+	db, span := startSpan(db, "GetSubscriptionsByUsers")
+	defer func() { endSpan(span, err) }()
+
+	result := make(map[uuid.UUID][]models.Subscription, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var subscriptions []models.Subscription
+	if err := database.ReadReplica(db).Scopes(tenantScope(tenant)).Where("user_id IN ?", userIDs).Order("started_at").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	for _, id := range userIDs {
+		result[id] = []models.Subscription{}
+	}
+	for _, sub := range subscriptions {
+		result[sub.UserID] = append(result[sub.UserID], sub)
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	database "subscribers/internal/db"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetSubscriptionsInvolvingUsers загружает одним запросом подписки, которые
+// пользователи оплачивают или делят с плательщиком, вместе с участниками —
+// как CalculateSpendingBreakdown, чтобы по ним можно было посчитать доли.
+// Пользователи, чьи расходы арендатору недоступны, получают пустой список.
+func GetSubscriptionsInvolvingUsers(db *gorm.DB, tenant models.Tenant, userIDs []uuid.UUID) (_ map[uuid.UUID][]models.Subscription, err error) {
+	db, span := startSpan(db, "GetSubscriptionsInvolvingUsers")
+	defer func() { endSpan(span, err) }()
+
+	result := make(map[uuid.UUID][]models.Subscription, len(userIDs))
+	allowed := make([]uuid.UUID, 0, len(userIDs))
+	for _, id := range userIDs {
+		result[id] = []models.Subscription{}
+		if authorizeRead(tenant, id) == nil {
+			allowed = append(allowed, id)
+		}
+	}
+	if len(allowed) == 0 {
+		return result, nil
+	}
+
+	var subscriptions []models.Subscription
+	err = database.ReadReplica(db).
+		Scopes(orgScope(tenant)).
+		Where("user_id IN ? OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id IN ?)", allowed, allowed).
+		Preload("Members").
+		Order("started_at").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range subscriptions {
+		for _, id := range allowed {
+			if involves(sub, id) {
+				result[id] = append(result[id], sub)
+			}
+		}
+	}
+	return result, nil
+}
+
+// involves — то же условие, что и involving, для уже загруженной подписки.
+func involves(sub models.Subscription, userID uuid.UUID) bool {
+	if sub.UserID == userID {
+		return true
+	}
+	for _, member := range sub.Members {
+		if member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func GetWebhooks(db *gorm.DB, tenant models.Tenant) (_ []models.WebhookEndpoint, err error) {
+	db, span := startSpan(db, "GetWebhooks")
+	defer func() { endSpan(span, err) }()
+
+	if err := authorizeWebhooks(tenant); err != nil {
+		return nil, err
+	}
+
+	var endpoints []models.WebhookEndpoint
+	if err := db.Scopes(orgScope(tenant)).Order("created_at").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImportCatalog загружает записи каталога одной транзакцией. Запись, название
+// которой уже сводится к сервису каталога, заменяет его; остальные создаются.
+func ImportCatalog(db *gorm.DB, reqs []models.CatalogServiceRequest) (created, updated int, err error) {
+	db, span := startSpan(db, "ImportCatalog")
+	defer func() { endSpan(span, err) }()
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, req := range reqs {
+			if req.Name == "" {
+				return fmt.Errorf("catalog entry without a name")
+			}
+			existing, err := ResolveServiceName(tx, req.Name)
+			if err != nil {
+				return err
+			}
+
+			var service models.CatalogService
+			if existing != nil {
+				service = *existing
+				updated++
+			} else {
+				service.ID = uuid.New()
+				created++
+			}
+			if err := saveCatalogService(tx, &service, req); err != nil {
+				return fmt.Errorf("%s: %w", req.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, updated, nil
+}
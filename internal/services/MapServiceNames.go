@@ -0,0 +1,70 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MapServiceNames сопоставляет свободные названия подписок (включая удалённые)
+// с каталогом. С apply подписки переименовываются в каноническое название и
+// получают service_id; события при этом не создаются.
+func MapServiceNames(db *gorm.DB, apply bool) (_ []models.ServiceNameMapping, err error) {
+	db, span := startSpan(db, "MapServiceNames")
+	defer func() { endSpan(span, err) }()
+
+	var catalog []models.CatalogService
+	if err := db.Find(&catalog).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.CatalogService, len(catalog))
+	for _, service := range catalog {
+		byID[service.ID] = service
+	}
+
+	var aliases []models.ServiceAlias
+	if err := db.Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+	byAlias := make(map[string]models.CatalogService, len(aliases))
+	for _, alias := range aliases {
+		byAlias[alias.Alias] = byID[alias.ServiceID]
+	}
+
+	var mappings []models.ServiceNameMapping
+	err = db.Unscoped().Model(&models.Subscription{}).
+		Select("service_name AS name, COUNT(*) AS subscriptions").
+		Group("service_name").
+		Order("service_name").
+		Scan(&mappings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i := range mappings {
+			service, ok := byAlias[models.NormalizeServiceName(mappings[i].Name)]
+			if !ok {
+				continue
+			}
+			mappings[i].CanonicalName = service.Name
+			mappings[i].ServiceID = &service.ID
+
+			if !apply {
+				continue
+			}
+			err := tx.Unscoped().Model(&models.Subscription{}).
+				Where("service_name = ?", mappings[i].Name).
+				Updates(map[string]interface{}{"service_name": service.Name, "service_id": service.ID}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
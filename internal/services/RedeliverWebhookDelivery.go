@@ -0,0 +1,36 @@
+package services
+
+import (
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RedeliverWebhookDelivery возвращает доставку в очередь с обнулённым счётчиком попыток.
+func RedeliverWebhookDelivery(db *gorm.DB, tenant models.Tenant, id uuid.UUID) (err error) {
+	db, span := startSpan(db, "RedeliverWebhookDelivery")
+	defer func() { endSpan(span, err) }()
+
+	if err := authorizeWebhooks(tenant); err != nil {
+		return err
+	}
+
+	result := db.Model(&models.WebhookDelivery{}).
+		Where("id = ?", id).
+		Where("endpoint_id IN (?)", tenantEndpoints(db, tenant)).
+		Updates(map[string]interface{}{
+			"status":          models.DeliveryStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now().UTC(),
+			"last_error":      "",
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RemoveMember исключает пользователя из организации. Его подписки остаются
+// в организации и доступны администраторам.
+func RemoveMember(db *gorm.DB, tenant models.Tenant, userID uuid.UUID) (err error) {
+	db, span := startSpan(db, "RemoveMember")
+	defer func() { endSpan(span, err) }()
+
+	if err := requireAdmin(tenant); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var member models.Membership
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&member, "org_id = ? AND user_id = ?", tenant.OrgID, userID).Error
+		if err != nil {
+			return err
+		}
+		if member.Role == models.RoleAdmin {
+			if err := ensureAnotherAdmin(tx, tenant.OrgID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&member).Error
+	})
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+	"subscribers/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ResolveServiceName ищет сервис каталога по названию или алиасу без учёта
+// регистра и лишних пробелов. Для названий вне каталога возвращает nil без ошибки.
+func ResolveServiceName(db *gorm.DB, name string) (_ *models.CatalogService, err error) {
+	db, span := startSpan(db, "ResolveServiceName")
+	defer func() { endSpan(span, err) }()
+
+	var alias models.ServiceAlias
+	err = db.First(&alias, "alias = ?", models.NormalizeServiceName(name)).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var service models.CatalogService
+	if err := db.Preload("Plans").First(&service, "id = ?", alias.ServiceID).Error; err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
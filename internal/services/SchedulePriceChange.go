@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+	"subscribers/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SchedulePriceChange планирует новую цену подписки с будущего месяца.
+// На один месяц может быть запланировано одно изменение: новое заменяет прежнее.
+func SchedulePriceChange(db *gorm.DB, tenant models.Tenant, subID uuid.UUID, req models.SchedulePriceChangeRequest) (_ *models.PriceChange, err error) {
+	db, span := startSpan(db, "SchedulePriceChange")
+	defer func() { endSpan(span, err) }()
+
+	effective, err := utils.ParseYearMonth(req.EffectiveDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective_date: %w", err)
+	}
+
+	var change models.PriceChange
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var sub models.Subscription
+		if err := tx.Scopes(tenantScope(tenant)).Preload("Members").First(&sub, "id = ?", subID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		current := models.YearMonth{Year: now.Year(), Month: now.Month()}
+		if !effective.After(current) {
+			return fmt.Errorf("%w: effective_date must be after %s", ErrInvalidPriceChange, current)
+		}
+		if !sub.ActiveIn(effective) {
+			return fmt.Errorf("%w: the subscription is not active in %s", ErrInvalidPriceChange, effective)
+		}
+		if sub.SplitRule != "" {
+			if err := validateSplit(sub.SplitRule, *req.Price, sub.UserID, sub.Members); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidSplit, err)
+			}
+		}
+
+		err := tx.Where("subscription_id = ? AND effective_from = ? AND applied_at IS NULL", subID, effective).
+			Delete(&models.PriceChange{}).Error
+		if err != nil {
+			return err
+		}
+
+		change = models.PriceChange{
+			ID:             uuid.New(),
+			SubscriptionID: subID,
+			EffectiveFrom:  effective,
+			Price:          *req.Price,
+		}
+		if err := tx.Create(&change).Error; err != nil {
+			return fmt.Errorf("error saving the price change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
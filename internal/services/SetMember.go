@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetMember добавляет пользователя в организацию или меняет его роль.
+// Доступно только администраторам.
+func SetMember(db *gorm.DB, tenant models.Tenant, userID uuid.UUID, req models.SetMemberRequest) (_ *models.Membership, err error) {
+	db, span := startSpan(db, "SetMember")
+	defer func() { endSpan(span, err) }()
+
+	if err := requireAdmin(tenant); err != nil {
+		return nil, err
+	}
+
+	var member models.Membership
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&member, "org_id = ? AND user_id = ?", tenant.OrgID, userID).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			member = models.Membership{OrgID: tenant.OrgID, UserID: userID, Role: req.Role}
+			return tx.Create(&member).Error
+		case err != nil:
+			return err
+		}
+
+		if member.Role == models.RoleAdmin && req.Role != models.RoleAdmin {
+			if err := ensureAnotherAdmin(tx, tenant.OrgID, userID); err != nil {
+				return err
+			}
+		}
+		member.Role = req.Role
+		return tx.Save(&member).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// ensureAnotherAdmin проверяет, что кроме userID в организации есть администратор.
+func ensureAnotherAdmin(tx *gorm.DB, orgID, userID uuid.UUID) error {
+	var admins int64
+	err := tx.Model(&models.Membership{}).
+		Where("org_id = ? AND role = ? AND user_id <> ?", orgID, models.RoleAdmin, userID).
+		Count(&admins).Error
+	if err != nil {
+		return err
+	}
+	if admins == 0 {
+		return ErrLastAdmin
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SetSubscriptionMembers заменяет участников подписки и правило разделения.
+// Менять их может плательщик или администратор организации; в организации
+// участниками могут быть только её члены.
+func SetSubscriptionMembers(db *gorm.DB, tenant models.Tenant, id uuid.UUID, req models.SetMembersRequest) (_ *models.Subscription, err error) {
+	db, span := startSpan(db, "SetSubscriptionMembers")
+	defer func() { endSpan(span, err) }()
+
+	members := make([]models.SubscriptionMember, 0, len(req.Members))
+	for _, m := range req.Members {
+		userID, err := uuid.Parse(m.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid UUID: %w", err)
+		}
+		member := models.SubscriptionMember{SubscriptionID: id, UserID: userID}
+		switch req.SplitRule {
+		case models.SplitPercent:
+			member.Percent = m.Percent
+		case models.SplitFixed:
+			member.Amount = m.Amount
+		}
+		members = append(members, member)
+	}
+
+	var sub models.Subscription
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(tenantScope(tenant)).Preload("Members").First(&sub, "id = ?", id).Error; err != nil {
+			return err
+		}
+		previous := sub
+		if err := validateSplit(req.SplitRule, sub.MonthlyPrice, sub.UserID, members); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSplit, err)
+		}
+		if !tenant.Personal() {
+			for _, member := range members {
+				if err := authorizeUser(tx, models.Tenant{OrgID: tenant.OrgID, Role: models.RoleAdmin}, member.UserID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.SubscriptionMember{}).Error; err != nil {
+			return err
+		}
+		if len(members) > 0 {
+			if err := tx.Create(&members).Error; err != nil {
+				return fmt.Errorf("error saving subscription members: %w", err)
+			}
+			sub.SplitRule = req.SplitRule
+		} else {
+			sub.SplitRule = ""
+		}
+		if err := tx.Model(&sub).Update("split_rule", sub.SplitRule).Error; err != nil {
+			return err
+		}
+
+		sub.Members = members
+		if err := emitEvent(tx, sub.OrgID, models.EventSubscriptionUpdated, sub.ID, sub); err != nil {
+			return err
+		}
+		return checkBudgets(tx, sub, &previous)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
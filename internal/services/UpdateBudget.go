@@ -0,0 +1,24 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func UpdateBudget(db *gorm.DB, tenant models.Tenant, id uuid.UUID, req models.UpdateBudgetRequest) (_ *models.Budget, err error) {
+	db, span := startSpan(db, "UpdateBudget")
+	defer func() { endSpan(span, err) }()
+
+	var budget models.Budget
+	if err := db.Scopes(tenantScope(tenant)).First(&budget, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	budget.Amount = req.Amount
+	if err := db.Save(&budget).Error; err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UpdateCatalogService заменяет запись каталога целиком. При смене
+// канонического названия подписки, сведённые к сервису, переименовываются.
+func UpdateCatalogService(db *gorm.DB, id uuid.UUID, req models.CatalogServiceRequest) (_ *models.CatalogService, err error) {
+	db, span := startSpan(db, "UpdateCatalogService")
+	defer func() { endSpan(span, err) }()
+
+	var service models.CatalogService
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&service, "id = ?", id).Error; err != nil {
+			return err
+		}
+		previousName := service.Name
+		if err := saveCatalogService(tx, &service, req); err != nil {
+			return err
+		}
+		if service.Name == previousName {
+			return nil
+		}
+		return tx.Unscoped().Model(&models.Subscription{}).
+			Where("service_id = ?", id).
+			Update("service_name", service.Name).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
@@ -2,30 +2,55 @@ package services
 
 import (
 	"fmt"
+	"strings"
 	"subscribers/internal/models"
 	"subscribers/internal/utils"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func UpdateSubscription(db *gorm.DB, id uuid.UUID, req models.UpdateSubscriptionRequest) error {
+func UpdateSubscription(db *gorm.DB, tenant models.Tenant, id uuid.UUID, req models.UpdateSubscriptionRequest) (err error) {
+	db, span := startSpan(db, "UpdateSubscription")
+	defer func() { endSpan(span, err) }()
+
 	var sub models.Subscription
 
-	if err := db.First(&sub, "id = ?", id).Error; err != nil {
+	if err := db.Scopes(tenantScope(tenant)).Preload("Members").Preload("Tags").First(&sub, "id = ?", id).Error; err != nil {
 		return err
 	}
 
+	previous := sub
+	wasEndedAt := sub.EndedAt
+	previousPrice := sub.MonthlyPrice
+
 	if req.ServiceName != nil {
-		sub.ServiceName = *req.ServiceName
+		service, err := ResolveServiceName(db, *req.ServiceName)
+		if err != nil {
+			return fmt.Errorf("service catalog lookup error: %w", err)
+		}
+		sub.ServiceName, sub.ServiceID = *req.ServiceName, nil
+		if service != nil {
+			sub.ServiceName, sub.ServiceID = service.Name, &service.ID
+		}
 	}
 	if req.Price != nil {
 		sub.MonthlyPrice = *req.Price
 	}
+	if req.BillingPeriod != nil {
+		sub.BillingPeriod = *req.BillingPeriod
+	}
+	if req.Category != nil {
+		sub.Category = *req.Category
+	}
+	if req.Tags != nil {
+		sub.Tags = subscriptionTags(sub, *req.Tags)
+	}
 	if req.StartDate != nil {
 		startYM, err := utils.ParseYearMonth(*req.StartDate)
 		if err != nil {
-			return fmt.Errorf("invalid start_date: %w", err)
+			return fmt.Errorf("%w: start_date: %v", ErrInvalidDate, err)
 		}
 		sub.StartedAt = startYM
 	}
@@ -36,15 +61,63 @@ func UpdateSubscription(db *gorm.DB, id uuid.UUID, req models.UpdateSubscription
 		} else {
 			endYM, err := utils.ParseYearMonth(*req.EndDate)
 			if err != nil {
-				return fmt.Errorf("invalid end_date: %w", err)
+				return fmt.Errorf("%w: end_date: %v", ErrInvalidDate, err)
 			}
 			sub.EndedAt = &endYM
 		}
 	}
 
-	if err := db.Save(&sub).Error; err != nil {
-		return fmt.Errorf("failed to update subscription: %w", err)
+	if sub.EndedAt != nil && sub.EndedAt.Before(sub.StartedAt) {
+		return fmt.Errorf("%w: end_date is before start_date", ErrInvalidDate)
+	}
+
+	if !strings.EqualFold(sub.ServiceName, previous.ServiceName) {
+		exists, err := utils.SubscriptionExists(db.Scopes(tenantScope(tenant)).Where("id <> ?", sub.ID), sub.UserID, sub.ServiceName)
+		if err != nil {
+			return fmt.Errorf("record search error: %w", err)
+		}
+		if exists {
+			return ErrSubscriptionExists
+		}
+	}
+
+	if sub.SplitRule != "" && sub.MonthlyPrice != previousPrice {
+		if err := validateSplit(sub.SplitRule, sub.MonthlyPrice, sub.UserID, sub.Members); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSplit, err)
+		}
 	}
 
-	return nil
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&sub).Error; err != nil {
+			return fmt.Errorf("failed to update subscription: %w", err)
+		}
+		if req.Tags != nil {
+			if err := tx.Where("subscription_id = ?", sub.ID).Delete(&models.SubscriptionTag{}).Error; err != nil {
+				return err
+			}
+			if len(sub.Tags) > 0 {
+				if err := tx.Create(&sub.Tags).Error; err != nil {
+					return fmt.Errorf("failed to update subscription tags: %w", err)
+				}
+			}
+		}
+		if err := emitEvent(tx, sub.OrgID, models.EventSubscriptionUpdated, sub.ID, sub); err != nil {
+			return err
+		}
+		if sub.MonthlyPrice != previousPrice {
+			if err := recordPriceChange(tx, sub, previousPrice); err != nil {
+				return err
+			}
+			data := models.PriceChangedData{Subscription: sub, PreviousPrice: previousPrice}
+			if err := emitEvent(tx, sub.OrgID, models.EventSubscriptionPriceChanged, sub.ID, data); err != nil {
+				return err
+			}
+		}
+		if sub.EndedAt != nil && (wasEndedAt == nil || *wasEndedAt != *sub.EndedAt) {
+			if err := emitEvent(tx, sub.OrgID, models.EventSubscriptionEnded, sub.ID, sub); err != nil {
+				return err
+			}
+		}
+		return checkBudgets(tx, sub, &previous)
+	})
 }
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// budgetHorizon — на сколько месяцев вперёд, начиная с текущего, изменение
+// подписки проверяется на превышение бюджетов.
+const budgetHorizon = 12
+
+// CalculateBudgetStatus считает расходы userID по бюджету в каждом месяце
+// периода from..to по подпискам, в которых он платит или участвует.
+func CalculateBudgetStatus(budget models.Budget, subscriptions []models.Subscription, userID uuid.UUID, from, to models.YearMonth) models.BudgetStatus {
+	status := models.BudgetStatus{Budget: budget, Months: []models.BudgetMonth{}}
+	for ym := from; !ym.After(to); ym = ym.AddMonths(1) {
+		spent := 0
+		for _, sub := range subscriptions {
+			spent += budgetSpend(budget, sub, userID, ym)
+		}
+		status.Months = append(status.Months, budgetMonth(budget, ym, spent))
+	}
+	return status
+}
+
+func budgetSpend(budget models.Budget, sub models.Subscription, userID uuid.UUID, ym models.YearMonth) int {
+	if !budget.Applies(sub) || !sub.ActiveIn(ym) {
+		return 0
+	}
+	return shareOf(sub, userID)
+}
+
+func budgetMonth(budget models.Budget, ym models.YearMonth, spent int) models.BudgetMonth {
+	return models.BudgetMonth{
+		Month:     ym,
+		Spent:     spent,
+		Remaining: budget.Amount - spent,
+		Over:      spent > budget.Amount,
+	}
+}
+
+// checkBudgets вызывается в транзакции после сохранения подписки sub и
+// публикует budget.exceeded для каждого бюджета плательщика и участников,
+// который это изменение вывело за лимит хотя бы в одном из ближайших
+// budgetHorizon месяцев. previous — подписка до изменения, nil при создании.
+func checkBudgets(tx *gorm.DB, sub models.Subscription, previous *models.Subscription) error {
+	space := models.Tenant{}
+	if sub.OrgID != nil {
+		space.OrgID = *sub.OrgID
+	}
+
+	users := []uuid.UUID{sub.UserID}
+	for _, member := range sub.Members {
+		users = append(users, member.UserID)
+	}
+	if previous != nil {
+		for _, member := range previous.Members {
+			users = append(users, member.UserID)
+		}
+	}
+
+	now := time.Now().UTC()
+	from := models.YearMonth{Year: now.Year(), Month: now.Month()}
+	to := from.AddMonths(budgetHorizon - 1)
+
+	checked := make(map[uuid.UUID]bool, len(users))
+	for _, userID := range users {
+		if checked[userID] {
+			continue
+		}
+		checked[userID] = true
+
+		var budgets []models.Budget
+		if err := tx.Scopes(orgScope(space)).Where("user_id = ?", userID).Find(&budgets).Error; err != nil {
+			return err
+		}
+		if len(budgets) == 0 {
+			continue
+		}
+
+		var subscriptions []models.Subscription
+		err := tx.Scopes(orgScope(space), involving(userID)).Preload("Members").Find(&subscriptions).Error
+		if err != nil {
+			return err
+		}
+
+		for _, budget := range budgets {
+			status := CalculateBudgetStatus(budget, subscriptions, userID, from, to)
+
+			var exceeded []models.BudgetMonth
+			for _, month := range status.Months {
+				before := month.Spent - budgetSpend(budget, sub, userID, month.Month)
+				if previous != nil {
+					before += budgetSpend(budget, *previous, userID, month.Month)
+				}
+				if month.Over && before <= budget.Amount {
+					exceeded = append(exceeded, month)
+				}
+			}
+			if len(exceeded) == 0 {
+				continue
+			}
+
+			data := models.BudgetExceededData{Budget: budget, SubscriptionID: sub.ID, Months: exceeded}
+			if err := emitEvent(tx, budget.OrgID, models.EventBudgetExceeded, budget.ID, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// budgetTarget приводит цель бюджета к виду, с которым сравниваются подписки:
+// категорию проверяет, название сервиса сводит к каталогу.
+func budgetTarget(db *gorm.DB, scope, target string) (string, error) {
+	switch scope {
+	case models.BudgetCategory:
+		if !models.IsCategory(target) {
+			return "", fmt.Errorf("unknown category %q", target)
+		}
+		return target, nil
+	case models.BudgetService:
+		service, err := ResolveServiceName(db, target)
+		if err != nil {
+			return "", err
+		}
+		if service != nil {
+			return service.Name, nil
+		}
+		return target, nil
+	default:
+		return "", nil
+	}
+}
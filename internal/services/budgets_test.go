@@ -0,0 +1,65 @@
+package services
+
+import (
+	"subscribers/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCalculateBudgetStatus(t *testing.T) {
+	userID := uuid.New()
+	jan := models.YearMonth{Year: 2025, Month: time.January}
+	feb, mar := jan.AddMonths(1), jan.AddMonths(2)
+
+	subs := []models.Subscription{
+		{UserID: userID, ServiceName: "GitHub Copilot", Category: models.CategorySoftware, MonthlyPrice: 300, StartedAt: jan},
+		{
+			UserID: userID, ServiceName: "JetBrains", Category: models.CategorySoftware, MonthlyPrice: 400, StartedAt: feb,
+			SplitRule: models.SplitEqual, Members: []models.SubscriptionMember{{UserID: uuid.New()}},
+		},
+		{UserID: userID, ServiceName: "Figma", Category: models.CategorySoftware, MonthlyPrice: 100, StartedAt: mar},
+		{UserID: userID, ServiceName: "Netflix", Category: models.CategoryEntertainment, MonthlyPrice: 1000, StartedAt: jan},
+	}
+
+	type month struct {
+		spent, remaining int
+		over             bool
+	}
+	tests := []struct {
+		name   string
+		budget models.Budget
+		want   []month
+	}{
+		{
+			name:   "category",
+			budget: models.Budget{Scope: models.BudgetCategory, Target: models.CategorySoftware, Amount: 500},
+			want:   []month{{300, 200, false}, {500, 0, false}, {600, -100, true}},
+		},
+		{
+			name:   "service",
+			budget: models.Budget{Scope: models.BudgetService, Target: "Netflix", Amount: 1000},
+			want:   []month{{1000, 0, false}, {1000, 0, false}, {1000, 0, false}},
+		},
+		{
+			name:   "overall",
+			budget: models.Budget{Scope: models.BudgetOverall, Amount: 1500},
+			want:   []month{{1300, 200, false}, {1500, 0, false}, {1600, -100, true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := CalculateBudgetStatus(tt.budget, subs, userID, jan, mar)
+			if len(status.Months) != len(tt.want) {
+				t.Fatalf("got %d months, want %d", len(status.Months), len(tt.want))
+			}
+			for i, got := range status.Months {
+				want := tt.want[i]
+				if got.Month != jan.AddMonths(i) || got.Spent != want.spent || got.Remaining != want.remaining || got.Over != want.over {
+					t.Errorf("month %d = %+v, want spent %d, remaining %d, over %v", i, got, want.spent, want.remaining, want.over)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// saveCatalogService записывает сервис из запроса и заменяет его алиасы и
+// тарифы. Вызывается внутри транзакции; service.ID должен быть заполнен.
+func saveCatalogService(tx *gorm.DB, service *models.CatalogService, req models.CatalogServiceRequest) error {
+	if req.Category != "" && !models.IsCategory(req.Category) {
+		return fmt.Errorf("unknown category %q", req.Category)
+	}
+	service.Name = req.Name
+	service.Category = req.Category
+	service.Website = req.Website
+
+	aliases := catalogAliases(req)
+	var taken models.ServiceAlias
+	err := tx.Where("alias IN ? AND service_id <> ?", aliases, service.ID).Limit(1).Find(&taken).Error
+	if err != nil {
+		return err
+	}
+	if taken.Alias != "" {
+		return fmt.Errorf("%w: %q", ErrAliasTaken, taken.Alias)
+	}
+
+	if err := tx.Omit(clause.Associations).Save(service).Error; err != nil {
+		return fmt.Errorf("error saving catalog service: %w", err)
+	}
+	if err := tx.Where("service_id = ?", service.ID).Delete(&models.ServiceAlias{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("service_id = ?", service.ID).Delete(&models.ServicePlan{}).Error; err != nil {
+		return err
+	}
+
+	service.Aliases = make([]models.ServiceAlias, 0, len(aliases))
+	for _, alias := range aliases {
+		service.Aliases = append(service.Aliases, models.ServiceAlias{Alias: alias, ServiceID: service.ID})
+	}
+	if err := tx.Create(&service.Aliases).Error; err != nil {
+		return fmt.Errorf("error saving service aliases: %w", err)
+	}
+
+	service.Plans = make([]models.ServicePlan, 0, len(req.Plans))
+	for _, plan := range req.Plans {
+		service.Plans = append(service.Plans, models.ServicePlan{ServiceID: service.ID, Name: plan.Name, Price: plan.Price})
+	}
+	if len(service.Plans) > 0 {
+		if err := tx.Create(&service.Plans).Error; err != nil {
+			return fmt.Errorf("error saving service plans: %w", err)
+		}
+	}
+	return nil
+}
+
+// catalogAliases — нормализованные алиасы без повторов, первым идёт
+// каноническое название.
+func catalogAliases(req models.CatalogServiceRequest) []string {
+	seen := make(map[string]bool, len(req.Aliases)+1)
+	var aliases []string
+	for _, name := range append([]string{req.Name}, req.Aliases...) {
+		alias := models.NormalizeServiceName(name)
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
@@ -0,0 +1,28 @@
+package services
+
+import "errors"
+
+var ErrSubscriptionExists = errors.New("A subscription already exists for this user and the service.")
+
+var (
+	// ErrForbidden — у пользователя нет прав на операцию в организации.
+	ErrForbidden = errors.New("operation is not allowed for this user")
+	// ErrNotMember — пользователь не состоит в организации.
+	ErrNotMember = errors.New("user is not a member of the organization")
+	// ErrLastAdmin — в организации должен остаться хотя бы один администратор.
+	ErrLastAdmin = errors.New("organization must keep at least one admin")
+	// ErrInvalidSplit — доли участников не сходятся со стоимостью подписки.
+	ErrInvalidSplit = errors.New("invalid cost split")
+	// ErrPeriodTooLong — помесячная раскладка длиннее maxSpendingMonths.
+	ErrPeriodTooLong = errors.New("period is too long")
+	// ErrAliasTaken — название уже принадлежит другой записи каталога.
+	ErrAliasTaken = errors.New("service name or alias is already in the catalog")
+	// ErrUnknownPlan — тарифа нет в каталоге.
+	ErrUnknownPlan = errors.New("unknown plan")
+	// ErrBudgetExists — у пользователя уже есть бюджет с той же областью.
+	ErrBudgetExists = errors.New("a budget with this scope already exists")
+	// ErrInvalidDate — дата не разбирается или окончание раньше начала.
+	ErrInvalidDate = errors.New("invalid date")
+	// ErrInvalidPriceChange — изменение цены нельзя запланировать на этот месяц.
+	ErrInvalidPriceChange = errors.New("invalid price change")
+)
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"subscribers/internal/outbox"
+	"subscribers/internal/webhooks"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// emitEvent записывает событие в outbox и ставит webhook-доставки
+// endpoint'ам пространства orgID. tx — транзакция, в которой выполняется
+// изменение подписки.
+func emitEvent(tx *gorm.DB, orgID *uuid.UUID, event string, aggregateID uuid.UUID, data interface{}) error {
+	if err := outbox.Record(tx, event, aggregateID, data); err != nil {
+		return err
+	}
+	return webhooks.Enqueue(tx, orgID, event, data)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"subscribers/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// subscriptionFilter применяет условия отбора к запросу подписок. Период
+// сравнивается с месяцем начала подписки.
+func subscriptionFilter(filter models.SubscriptionFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.ServiceName != "" {
+			db = db.Where("service_name = ?", filter.ServiceName)
+		}
+		if filter.Category != "" {
+			db = db.Where("category = ?", filter.Category)
+		}
+		for _, tag := range models.NormalizeTags(filter.Tags) {
+			db = db.Where("id IN (SELECT subscription_id FROM subscription_tags WHERE tag = ?)", tag)
+		}
+		if filter.StartYM != nil {
+			db = db.Where(
+				"(EXTRACT(YEAR FROM started_at) * 100 + EXTRACT(MONTH FROM started_at)) >= ?",
+				filter.StartYM.Year*100+int(filter.StartYM.Month),
+			)
+		}
+		if filter.EndYM != nil {
+			db = db.Where(
+				"(EXTRACT(YEAR FROM started_at) * 100 + EXTRACT(MONTH FROM started_at)) <= ?",
+				filter.EndYM.Year*100+int(filter.EndYM.Month),
+			)
+		}
+		return db
+	}
+}
+
+// subscriptionTags — строки subscription_tags для нормализованных тегов.
+func subscriptionTags(sub models.Subscription, tags []string) []models.SubscriptionTag {
+	result := make([]models.SubscriptionTag, 0, len(tags))
+	for _, tag := range models.NormalizeTags(tags) {
+		result = append(result, models.SubscriptionTag{SubscriptionID: sub.ID, Tag: tag})
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// priceIncreaseLookback — за какой срок применённые повышения цены попадают в находки.
+	priceIncreaseLookback = 90 * 24 * time.Hour
+	// forgottenAfterMonths — через сколько месяцев без изменений подписка считается забытой.
+	forgottenAfterMonths = 24
+	// spikeTrailingMonths — за сколько предыдущих месяцев считается средний расход.
+	spikeTrailingMonths = 3
+	// spikePercent — во сколько процентов от среднего расход месяца считается скачком.
+	spikePercent = 150
+	// analyzeBatchUsers — сколько пользователей AnalyzeSubscriptions обрабатывает за раз.
+	analyzeBatchUsers = 500
+)
+
+// priceIncreaseInsights — находки по применённым повышениям цены; subs —
+// подписки изменений по ID. Находка адресуется плательщику.
+func priceIncreaseInsights(changes []models.PriceChange, subs map[uuid.UUID]models.Subscription) []models.Insight {
+	var insights []models.Insight
+	for _, change := range changes {
+		sub, ok := subs[change.SubscriptionID]
+		if !ok || change.PreviousPrice == nil || change.Price <= *change.PreviousPrice {
+			continue
+		}
+		insights = append(insights, models.Insight{
+			UserID:         sub.UserID,
+			OrgID:          sub.OrgID,
+			Kind:           models.InsightPriceIncrease,
+			Key:            change.ID.String(),
+			SubscriptionID: &sub.ID,
+			Month:          change.EffectiveFrom,
+			Amount:         change.Price,
+			PreviousAmount: *change.PreviousPrice,
+			Message:        fmt.Sprintf("%s price increased from %d to %d", sub.ServiceName, *change.PreviousPrice, change.Price),
+		})
+	}
+	return insights
+}
+
+// duplicateInsights ищет у пользователя несколько действующих подписок на
+// один сервис каталога, в том числе под разными названиями. aliases —
+// нормализованные названия и алиасы каталога с ID сервиса. Самая ранняя
+// подписка считается основной, остальные — её дубликатами.
+func duplicateInsights(subs []models.Subscription, aliases map[string]uuid.UUID, current models.YearMonth) []models.Insight {
+	type groupKey struct {
+		user, org, service uuid.UUID
+	}
+	groups := make(map[groupKey][]models.Subscription)
+	for _, sub := range subs {
+		if !sub.ActiveIn(current) {
+			continue
+		}
+		serviceID, ok := aliases[models.NormalizeServiceName(sub.ServiceName)]
+		if sub.ServiceID != nil {
+			serviceID, ok = *sub.ServiceID, true
+		}
+		if !ok {
+			continue
+		}
+		key := groupKey{user: sub.UserID, service: serviceID}
+		if sub.OrgID != nil {
+			key.org = *sub.OrgID
+		}
+		groups[key] = append(groups[key], sub)
+	}
+
+	var insights []models.Insight
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].StartedAt != group[j].StartedAt {
+				return group[i].StartedAt.Before(group[j].StartedAt)
+			}
+			return group[i].ID.String() < group[j].ID.String()
+		})
+		original := group[0]
+		for _, sub := range group[1:] {
+			insights = append(insights, models.Insight{
+				UserID:         sub.UserID,
+				OrgID:          sub.OrgID,
+				Kind:           models.InsightDuplicate,
+				Key:            sub.ID.String(),
+				SubscriptionID: &sub.ID,
+				RelatedID:      &original.ID,
+				Month:          sub.StartedAt,
+				Amount:         sub.MonthlyPrice,
+				Message:        fmt.Sprintf("%q looks like a duplicate of %q", sub.ServiceName, original.ServiceName),
+			})
+		}
+	}
+	return insights
+}
+
+// forgottenInsights ищет бессрочные подписки, которые действуют и не
+// менялись дольше forgottenAfterMonths месяцев.
+func forgottenInsights(subs []models.Subscription, now time.Time) []models.Insight {
+	current := models.YearMonth{Year: now.Year(), Month: now.Month()}
+	threshold := now.AddDate(0, -forgottenAfterMonths, 0)
+
+	var insights []models.Insight
+	for _, sub := range subs {
+		if sub.EndedAt != nil || !sub.ActiveIn(current) {
+			continue
+		}
+		if current.Index()-sub.StartedAt.Index() < forgottenAfterMonths {
+			continue
+		}
+		if sub.UpdatedAt != nil && sub.UpdatedAt.After(threshold) {
+			continue
+		}
+		insights = append(insights, models.Insight{
+			UserID:         sub.UserID,
+			OrgID:          sub.OrgID,
+			Kind:           models.InsightForgotten,
+			Key:            sub.ID.String(),
+			SubscriptionID: &sub.ID,
+			Month:          sub.StartedAt,
+			Amount:         sub.MonthlyPrice,
+			Message:        fmt.Sprintf("%s has been active since %s without changes", sub.ServiceName, sub.StartedAt),
+		})
+	}
+	return insights
+}
+
+// spikeInsights сравнивает расходы каждого пользователя в текущем месяце со
+// средним за spikeTrailingMonths предыдущих месяцев, отдельно в личном
+// пространстве и в каждой организации. По разделённым подпискам учитывается
+// доля пользователя.
+func spikeInsights(subs []models.Subscription, current models.YearMonth) []models.Insight {
+	type spaceKey struct {
+		user, org uuid.UUID
+	}
+	// totals[i] — расходы за current-spikeTrailingMonths+i месяц.
+	totals := make(map[spaceKey]*[spikeTrailingMonths + 1]int)
+	from := current.AddMonths(-spikeTrailingMonths)
+	for _, sub := range subs {
+		users := []uuid.UUID{sub.UserID}
+		for _, member := range sub.Members {
+			users = append(users, member.UserID)
+		}
+		for _, userID := range users {
+			key := spaceKey{user: userID}
+			if sub.OrgID != nil {
+				key.org = *sub.OrgID
+			}
+			for i := 0; i <= spikeTrailingMonths; i++ {
+				if !sub.ActiveIn(from.AddMonths(i)) {
+					continue
+				}
+				if totals[key] == nil {
+					totals[key] = new([spikeTrailingMonths + 1]int)
+				}
+				totals[key][i] += shareOf(sub, userID)
+			}
+		}
+	}
+
+	var insights []models.Insight
+	for key, months := range totals {
+		trailing := 0
+		for _, total := range months[:spikeTrailingMonths] {
+			trailing += total
+		}
+		average := trailing / spikeTrailingMonths
+		spent := months[spikeTrailingMonths]
+		if average == 0 || spent*100 < average*spikePercent {
+			continue
+		}
+		insight := models.Insight{
+			UserID:         key.user,
+			Kind:           models.InsightSpendingSpike,
+			Key:            current.String(),
+			Month:          current,
+			Amount:         spent,
+			PreviousAmount: average,
+			Message:        fmt.Sprintf("spending in %s is %d against an average of %d", current, spent, average),
+		}
+		if key.org != uuid.Nil {
+			org := key.org
+			insight.OrgID = &org
+			insight.Key = current.String() + "/" + org.String()
+		}
+		insights = append(insights, insight)
+	}
+	return insights
+}
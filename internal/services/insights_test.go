@@ -0,0 +1,128 @@
+package services
+
+import (
+	"subscribers/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPriceIncreaseInsights(t *testing.T) {
+	sub := models.Subscription{ID: uuid.New(), UserID: uuid.New(), ServiceName: "Netflix"}
+	subs := map[uuid.UUID]models.Subscription{sub.ID: sub}
+	price := func(p int) *int { return &p }
+	month := models.YearMonth{Year: 2025, Month: time.March}
+
+	changes := []models.PriceChange{
+		{ID: uuid.New(), SubscriptionID: sub.ID, EffectiveFrom: month, Price: 799, PreviousPrice: price(599)},
+		{ID: uuid.New(), SubscriptionID: sub.ID, EffectiveFrom: month, Price: 499, PreviousPrice: price(599)},
+		{ID: uuid.New(), SubscriptionID: sub.ID, EffectiveFrom: month, Price: 599, PreviousPrice: price(599)},
+		{ID: uuid.New(), SubscriptionID: sub.ID, EffectiveFrom: month, Price: 999},
+		{ID: uuid.New(), SubscriptionID: uuid.New(), EffectiveFrom: month, Price: 999, PreviousPrice: price(1)},
+	}
+
+	insights := priceIncreaseInsights(changes, subs)
+	if len(insights) != 1 {
+		t.Fatalf("got %d insights, want 1: %+v", len(insights), insights)
+	}
+	got := insights[0]
+	if got.Kind != models.InsightPriceIncrease || got.UserID != sub.UserID || got.Key != changes[0].ID.String() ||
+		got.Amount != 799 || got.PreviousAmount != 599 || got.Month != month {
+		t.Errorf("insight = %+v", got)
+	}
+}
+
+func TestDuplicateInsights(t *testing.T) {
+	userID, otherUser, orgID := uuid.New(), uuid.New(), uuid.New()
+	netflix := uuid.New()
+	aliases := map[string]uuid.UUID{"netflix": netflix, "нетфликс": netflix}
+	current := models.YearMonth{Year: 2025, Month: time.June}
+	ended := current.AddMonths(-1)
+
+	original := models.Subscription{ID: uuid.New(), UserID: userID, ServiceName: "Netflix", ServiceID: &netflix, StartedAt: current.AddMonths(-12)}
+	duplicate := models.Subscription{ID: uuid.New(), UserID: userID, ServiceName: "Нетфликс", StartedAt: current.AddMonths(-2)}
+	subs := []models.Subscription{
+		duplicate,
+		original,
+		// Завершённая подписка, чужая подписка, подписка в организации и
+		// сервис вне каталога дубликатами не считаются.
+		{ID: uuid.New(), UserID: userID, ServiceName: "Netflix", StartedAt: current.AddMonths(-24), EndedAt: &ended},
+		{ID: uuid.New(), UserID: otherUser, ServiceName: "Netflix", StartedAt: current},
+		{ID: uuid.New(), UserID: userID, ServiceName: "Netflix", OrgID: &orgID, StartedAt: current},
+		{ID: uuid.New(), UserID: userID, ServiceName: "Local Gym", StartedAt: current},
+		{ID: uuid.New(), UserID: userID, ServiceName: "Local Gym", StartedAt: current},
+	}
+
+	insights := duplicateInsights(subs, aliases, current)
+	if len(insights) != 1 {
+		t.Fatalf("got %d insights, want 1: %+v", len(insights), insights)
+	}
+	got := insights[0]
+	if got.Kind != models.InsightDuplicate || *got.SubscriptionID != duplicate.ID || *got.RelatedID != original.ID {
+		t.Errorf("insight = %+v, want %s as a duplicate of %s", got, duplicate.ID, original.ID)
+	}
+}
+
+func TestForgottenInsights(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)
+	current := models.YearMonth{Year: 2026, Month: time.October}
+	old := current.AddMonths(-forgottenAfterMonths)
+	recent := now.AddDate(0, -1, 0)
+	longAgo := now.AddDate(-3, 0, 0)
+	future := current.AddMonths(6)
+
+	tests := []struct {
+		name string
+		sub  models.Subscription
+		want bool
+	}{
+		{"old and never updated", models.Subscription{StartedAt: old}, true},
+		{"old and updated long ago", models.Subscription{StartedAt: old, UpdatedAt: &longAgo}, true},
+		{"old but updated recently", models.Subscription{StartedAt: old, UpdatedAt: &recent}, false},
+		{"younger than the threshold", models.Subscription{StartedAt: old.AddMonths(1)}, false},
+		{"with an end date", models.Subscription{StartedAt: old, EndedAt: &future}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.sub.ID, tt.sub.UserID = uuid.New(), uuid.New()
+			insights := forgottenInsights([]models.Subscription{tt.sub}, now)
+			if got := len(insights) == 1; got != tt.want {
+				t.Fatalf("got %d insights, want forgotten: %v", len(insights), tt.want)
+			}
+			if tt.want && (insights[0].Kind != models.InsightForgotten || *insights[0].SubscriptionID != tt.sub.ID) {
+				t.Errorf("insight = %+v", insights[0])
+			}
+		})
+	}
+}
+
+func TestSpikeInsights(t *testing.T) {
+	current := models.YearMonth{Year: 2025, Month: time.April}
+	start := current.AddMonths(-spikeTrailingMonths)
+	spiking, steady, member, orgID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	subs := []models.Subscription{
+		{UserID: spiking, MonthlyPrice: 100, StartedAt: start},
+		{UserID: spiking, MonthlyPrice: 200, StartedAt: current},
+		{UserID: spiking, MonthlyPrice: 1000, StartedAt: start, OrgID: &orgID},
+		{UserID: steady, MonthlyPrice: 100, StartedAt: start},
+		{UserID: steady, MonthlyPrice: 40, StartedAt: current},
+		// У плательщика и участника не было расходов до текущего месяца:
+		// сравнивать не с чем.
+		{
+			UserID: uuid.New(), MonthlyPrice: 400, StartedAt: current,
+			SplitRule: models.SplitEqual, Members: []models.SubscriptionMember{{UserID: member}},
+		},
+	}
+
+	insights := spikeInsights(subs, current)
+	if len(insights) != 1 {
+		t.Fatalf("got %d insights, want 1: %+v", len(insights), insights)
+	}
+	got := insights[0]
+	if got.Kind != models.InsightSpendingSpike || got.UserID != spiking || got.OrgID != nil ||
+		got.Amount != 300 || got.PreviousAmount != 100 || got.Key != current.String() {
+		t.Errorf("insight = %+v", got)
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"fmt"
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// recordPriceChange записывает в историю цен изменение, сделанное сразу, а не
+// по расписанию: оно действует с текущего месяца и уже применено.
+func recordPriceChange(tx *gorm.DB, sub models.Subscription, previousPrice int) error {
+	now := time.Now().UTC()
+	change := models.PriceChange{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		EffectiveFrom:  models.YearMonth{Year: now.Year(), Month: now.Month()},
+		Price:          sub.MonthlyPrice,
+		PreviousPrice:  &previousPrice,
+		AppliedAt:      &now,
+	}
+	if err := tx.Create(&change).Error; err != nil {
+		return fmt.Errorf("error saving the price history: %w", err)
+	}
+	return nil
+}
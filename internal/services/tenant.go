@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"subscribers/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type tenantKey struct{}
+
+// WithTenant кладёт арендатора запроса в контекст; его достают транспортные
+// слои (REST, gRPC, GraphQL) и передают в сервисы явно.
+func WithTenant(ctx context.Context, tenant models.Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom возвращает арендатора из контекста; без него — личное пространство.
+func TenantFrom(ctx context.Context) models.Tenant {
+	tenant, _ := ctx.Value(tenantKey{}).(models.Tenant)
+	return tenant
+}
+
+// ResolveTenant проверяет, что userID состоит в организации orgID, и
+// возвращает арендатора с его ролью.
+func ResolveTenant(db *gorm.DB, orgID, userID uuid.UUID) (_ models.Tenant, err error) {
+	db, span := startSpan(db, "ResolveTenant")
+	defer func() { endSpan(span, err) }()
+
+	var org models.Organization
+	if err := db.First(&org, "id = ?", orgID).Error; err != nil {
+		return models.Tenant{}, err
+	}
+
+	var membership models.Membership
+	err = db.First(&membership, "org_id = ? AND user_id = ?", orgID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Tenant{}, ErrNotMember
+	}
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	return models.Tenant{OrgID: orgID, UserID: userID, Role: membership.Role}, nil
+}
+
+// tenantScope ограничивает запрос к подпискам данными арендатора: в личном
+// пространстве — подписками без организации, в организации — её подписками,
+// а обычному участнику — только его собственными.
+func tenantScope(tenant models.Tenant) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(orgScope(tenant))
+		if !tenant.Personal() && !tenant.IsAdmin() {
+			db = db.Where("user_id = ?", tenant.UserID)
+		}
+		return db
+	}
+}
+
+// orgScope — только граница пространства, без ограничения по владельцу;
+// доступ к чужим подпискам проверяется отдельно (authorizeRead).
+func orgScope(tenant models.Tenant) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenant.Personal() {
+			return db.Where("org_id IS NULL")
+		}
+		return db.Where("org_id = ?", tenant.OrgID)
+	}
+}
+
+// involving — подписки, которые userID оплачивает или делит с плательщиком.
+func involving(userID uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"user_id = ? OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id = ?)",
+			userID, userID,
+		)
+	}
+}
+
+// authorizeUser проверяет, может ли арендатор работать с подписками userID:
+// участник — только со своими, администратор — с подписками участников.
+func authorizeUser(db *gorm.DB, tenant models.Tenant, userID uuid.UUID) error {
+	switch {
+	case tenant.Personal():
+		return nil
+	case !tenant.IsAdmin():
+		if userID != tenant.UserID {
+			return ErrForbidden
+		}
+		return nil
+	}
+
+	var count int64
+	err := db.Model(&models.Membership{}).
+		Where("org_id = ? AND user_id = ?", tenant.OrgID, userID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotMember
+	}
+	return nil
+}
+
+// authorizeRead — участник видит только свои подписки, администратор и
+// личное пространство — подписки любого пользователя в пределах tenantScope.
+func authorizeRead(tenant models.Tenant, userID uuid.UUID) error {
+	if tenant.Personal() || tenant.IsAdmin() || userID == tenant.UserID {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// requireAdmin — для операций над организацией целиком.
+func requireAdmin(tenant models.Tenant) error {
+	if !tenant.IsAdmin() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeWebhooks — endpoint получает события всего пространства, поэтому
+// в организации webhook'ами управляют только администраторы.
+func authorizeWebhooks(tenant models.Tenant) error {
+	if tenant.Personal() {
+		return nil
+	}
+	return requireAdmin(tenant)
+}
+
+// tenantEndpoints — подзапрос ID webhook endpoint'ов пространства арендатора;
+// через него доставки ограничиваются пространством.
+func tenantEndpoints(db *gorm.DB, tenant models.Tenant) *gorm.DB {
+	return db.Model(&models.WebhookEndpoint{}).Scopes(orgScope(tenant)).Select("id")
+}
+
+// orgIDOf — значение колонки org_id для новых подписок арендатора.
+func orgIDOf(tenant models.Tenant) *uuid.UUID {
+	if tenant.Personal() {
+		return nil
+	}
+	orgID := tenant.OrgID
+	return &orgID
+}
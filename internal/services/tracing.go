@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+	"subscribers/internal/tracing"
+	"subscribers/logger"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// serviceSpan — span сервисного вызова и данные для debug лога о нём.
+type serviceSpan struct {
+	trace.Span
+	name  string
+	start time.Time
+	log   *zap.SugaredLogger
+}
+
+// startSpan открывает span сервисного вызова и привязывает его к запросам
+// GORM, чтобы SQL spans попадали внутрь.
+func startSpan(db *gorm.DB, name string) (*gorm.DB, *serviceSpan) {
+	ctx, span := tracing.Start(db.Statement.Context, "services."+name)
+	return db.WithContext(ctx), &serviceSpan{
+		Span:  span,
+		name:  name,
+		start: time.Now(),
+		log:   logger.ForComponent(logger.FromContext(ctx), logger.ComponentServices),
+	}
+}
+
+// endSpan закрывает span. Отсутствие записи — ожидаемый исход, а не ошибка.
+func endSpan(span *serviceSpan, err error) {
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		tracing.RecordError(span, err)
+	}
+	span.End()
+
+	span.log.Debugw("Service call finished",
+		"call", span.name,
+		"duration_ms", time.Since(span.start).Milliseconds(),
+		"error", err,
+	)
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// GormPlugin создаёт span на каждый SQL запрос. Родителем становится span
+// из контекста запроса, поэтому для трассировки запросы должны выполняться
+// через db.WithContext(ctx).
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "subscribers:tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("tracing:before_create", before("create")),
+		cb.Create().After("*").Register("tracing:after_create", after),
+		cb.Query().Before("*").Register("tracing:before_query", before("select")),
+		cb.Query().After("*").Register("tracing:after_query", after),
+		cb.Update().Before("*").Register("tracing:before_update", before("update")),
+		cb.Update().After("*").Register("tracing:after_update", after),
+		cb.Delete().Before("*").Register("tracing:before_delete", before("delete")),
+		cb.Delete().After("*").Register("tracing:after_delete", after),
+		cb.Row().Before("*").Register("tracing:before_row", before("row")),
+		cb.Row().After("*").Register("tracing:after_row", after),
+		cb.Raw().Before("*").Register("tracing:before_raw", before("raw")),
+		cb.Raw().After("*").Register("tracing:after_raw", after),
+	)
+}
+
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+		// Без входящего span'а SQL запросы фоновых воркеров не порождают
+		// отдельных трасс на каждый опрос очереди.
+		if !trace.SpanFromContext(db.Statement.Context).SpanContext().IsValid() {
+			return
+		}
+
+		ctx, span := Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBCollectionName(db.Statement.Table),
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"gorm.io/gorm"
 )
 
+// SubscriptionExists сравнивает названия без учёта регистра, чтобы свободные
+// названия вне каталога тоже не дублировались.
 func SubscriptionExists(db *gorm.DB, userID uuid.UUID, serviceName string) (bool, error) {
 	var existing models.Subscription
-	err := db.Where("user_id = ? AND service_name = ?", userID, serviceName).First(&existing).Error
+	err := db.Where("user_id = ? AND LOWER(service_name) = LOWER(?)", userID, serviceName).First(&existing).Error
 	if err == nil {
 		return true, nil
 	}
@@ -0,0 +1,234 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"subscribers/internal/models"
+	"subscribers/logger"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Options struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Timeout      time.Duration
+}
+
+type Dispatcher struct {
+	db      *gorm.DB
+	client  *http.Client
+	opts    Options
+	running atomic.Bool
+}
+
+func NewDispatcher(db *gorm.DB, opts Options) *Dispatcher {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 20
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 8
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 30 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Hour
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	return &Dispatcher{
+		db:     db,
+		client: &http.Client{Timeout: opts.Timeout},
+		opts:   opts,
+	}
+}
+
+// Run опрашивает очередь доставок, пока не будет отменён ctx.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.running.Store(true)
+	defer d.running.Store(false)
+
+	logger.SugaredLogger.Info("Webhook dispatcher started")
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.processBatch(ctx); err != nil {
+			logger.SugaredLogger.Errorf("Webhook dispatch error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.SugaredLogger.Info("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) Running() bool {
+	return d.running.Load()
+}
+
+func (d *Dispatcher) processBatch(ctx context.Context) error {
+	deliveries, leasedUntil, err := d.claim(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Доставку, которая может не успеть отправиться до конца аренды, не
+		// трогаем: после истечения аренды её возьмёт любой экземпляр.
+		if time.Now().UTC().Add(d.opts.Timeout).After(leasedUntil) {
+			return nil
+		}
+		d.deliver(ctx, delivery)
+	}
+	return nil
+}
+
+// claim выбирает готовые к отправке доставки и сдвигает их next_attempt_at
+// на время, за которое все они успеют отправиться одна за другой, чтобы
+// параллельные экземпляры не взяли их повторно. Возвращает конец аренды.
+func (d *Dispatcher) claim(ctx context.Context) ([]models.WebhookDelivery, time.Time, error) {
+	var deliveries []models.WebhookDelivery
+	now := time.Now().UTC()
+	var leasedUntil time.Time
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Order("next_attempt_at").
+			Limit(d.opts.BatchSize).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]interface{}, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		// Каждая отправка занимает не больше Timeout; ещё один Timeout — запас.
+		leasedUntil = now.Add(time.Duration(len(deliveries)+1) * d.opts.Timeout)
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leasedUntil).Error
+	})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return deliveries, leasedUntil, nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) {
+	var endpoint models.WebhookEndpoint
+	if err := d.db.WithContext(ctx).First(&endpoint, "id = ?", delivery.EndpointID).Error; err != nil {
+		d.markFailed(ctx, delivery, 0, fmt.Errorf("endpoint is unavailable: %w", err), true)
+		return
+	}
+
+	status, err := d.send(ctx, endpoint, delivery)
+	if err != nil && ctx.Err() != nil {
+		// Остановка сервиса — не попытка доставки: после истечения аренды
+		// доставка будет отправлена заново.
+		return
+	}
+	if err != nil {
+		d.markFailed(ctx, delivery, status, err, false)
+		return
+	}
+
+	now := time.Now().UTC()
+	err = d.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          models.DeliveryStatusDelivered,
+			"attempts":        delivery.Attempts + 1,
+			"response_status": status,
+			"last_error":      "",
+			"delivered_at":    now,
+		}).Error
+	if err != nil {
+		logger.SugaredLogger.Errorf("Failed to mark webhook delivery %s as delivered: %v", delivery.ID, err)
+		return
+	}
+	logger.SugaredLogger.Infof("Webhook %s delivered to %s", delivery.Event, endpoint.URL)
+}
+
+func (d *Dispatcher) send(ctx context.Context, endpoint models.WebhookEndpoint, delivery models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) markFailed(ctx context.Context, delivery models.WebhookDelivery, status int, cause error, final bool) {
+	attempts := delivery.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":        attempts,
+		"response_status": status,
+		"last_error":      cause.Error(),
+	}
+
+	if final || attempts >= d.opts.MaxAttempts {
+		updates["status"] = models.DeliveryStatusFailed
+		logger.SugaredLogger.Warnf("Webhook delivery %s moved to dead letter after %d attempts: %v", delivery.ID, attempts, cause)
+	} else {
+		updates["next_attempt_at"] = time.Now().UTC().Add(d.backoff(attempts))
+		logger.SugaredLogger.Warnf("Webhook delivery %s failed (attempt %d): %v", delivery.ID, attempts, cause)
+	}
+
+	err := d.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(updates).Error
+	if err != nil {
+		logger.SugaredLogger.Errorf("Failed to update webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// backoff растёт экспоненциально: base, 2*base, 4*base... но не больше MaxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.opts.MaxBackoff {
+			return d.opts.MaxBackoff
+		}
+	}
+	return delay
+}
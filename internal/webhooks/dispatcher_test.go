@@ -0,0 +1,82 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"subscribers/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(nil, Options{BaseBackoff: 30 * time.Second, MaxBackoff: time.Hour})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		secret, body, want string
+	}{
+		{
+			secret: "key",
+			body:   "The quick brown fox jumps over the lazy dog",
+			want:   "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		},
+		{
+			secret: "",
+			body:   "",
+			want:   "sha256=b613679a0814d9ec772f95d778c35fc5ff1697c493715653c6c712144292c5ad",
+		},
+	}
+	for _, tt := range tests {
+		if got := Sign(tt.secret, []byte(tt.body)); got != tt.want {
+			t.Errorf("Sign(%q, %q) = %s, want %s", tt.secret, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	endpoint := models.WebhookEndpoint{Secret: "0123456789abcdef"}
+	delivery := models.WebhookDelivery{ID: uuid.New(), Event: "subscription.created", Payload: []byte(`{"id":"1"}`)}
+
+	for _, status := range []int{http.StatusNoContent, http.StatusInternalServerError} {
+		var got *http.Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			w.WriteHeader(status)
+		}))
+		endpoint.URL = server.URL
+
+		code, err := NewDispatcher(nil, Options{}).send(context.Background(), endpoint, delivery)
+		server.Close()
+
+		if code != status || (err != nil) != (status >= 300) {
+			t.Errorf("send with response %d = %d, %v", status, code, err)
+		}
+		if got.Header.Get(HeaderEvent) != delivery.Event || got.Header.Get(HeaderDelivery) != delivery.ID.String() {
+			t.Errorf("event headers = %q, %q", got.Header.Get(HeaderEvent), got.Header.Get(HeaderDelivery))
+		}
+		if want := Sign(endpoint.Secret, delivery.Payload); got.Header.Get(HeaderSignature) != want {
+			t.Errorf("signature = %q, want %q", got.Header.Get(HeaderSignature), want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"subscribers/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Enqueue создаёт доставки события для endpoint'ов организации orgID (nil —
+// личного пространства), подписанных на него. Вызывается внутри транзакции
+// изменения, чтобы событие не потерялось и не появилось без самого изменения.
+func Enqueue(db *gorm.DB, orgID *uuid.UUID, event string, data interface{}) error {
+	query := db.Where("org_id IS NULL")
+	if orgID != nil {
+		query = db.Where("org_id = ?", *orgID)
+	}
+	var endpoints []models.WebhookEndpoint
+	if err := query.Find(&endpoints).Error; err != nil {
+		return fmt.Errorf("failed to load webhook endpoints: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var deliveries []models.WebhookDelivery
+	for _, endpoint := range endpoints {
+		if !endpoint.Accepts(event) {
+			continue
+		}
+
+		id := uuid.New()
+		payload, err := json.Marshal(models.Event{
+			ID:         id,
+			Event:      event,
+			OccurredAt: now,
+			Data:       data,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode webhook payload: %w", err)
+		}
+
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            id,
+			EndpointID:    endpoint.ID,
+			Event:         event,
+			Payload:       payload,
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := db.Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to save webhook deliveries: %w", err)
+	}
+	return nil
+}
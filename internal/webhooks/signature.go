@@ -0,0 +1,21 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign возвращает подпись тела в формате "sha256=<hex>".
+// Получатель проверяет её, вычисляя HMAC-SHA256 от сырого тела своим секретом.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,22 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type contextKey struct{}
+
+// WithContext кладёт логгер с полями запроса в контекст.
+func WithContext(ctx context.Context, l *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext возвращает логгер запроса, а вне запроса — глобальный логгер.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if l, ok := ctx.Value(contextKey{}).(*zap.SugaredLogger); ok {
+		return l
+	}
+	return SugaredLogger
+}
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Компоненты, для которых уровень можно задать отдельно от глобального.
+const (
+	ComponentHandlers = "handlers"
+	ComponentServices = "services"
+	ComponentDB       = "db"
+)
+
+var Components = []string{ComponentHandlers, ComponentServices, ComponentDB}
+
+var globalLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
+
+// override — собственный уровень компонента; пока set == false,
+// компонент использует глобальный уровень.
+type override struct {
+	level zap.AtomicLevel
+	set   atomic.Bool
+}
+
+// overrides заполняется в init и дальше только читается.
+var overrides = map[string]*override{}
+
+var (
+	mu      sync.Mutex
+	reverts = map[string]*time.Timer{}
+)
+
+func init() {
+	for _, component := range Components {
+		overrides[component] = &override{level: zap.NewAtomicLevel()}
+	}
+}
+
+// componentLevel — уровень компонента: собственный, если задан, иначе глобальный.
+type componentLevel string
+
+func (c componentLevel) Enabled(lvl zapcore.Level) bool {
+	if o, ok := overrides[string(c)]; ok && o.set.Load() {
+		return o.level.Enabled(lvl)
+	}
+	return globalLevel.Enabled(lvl)
+}
+
+// levelCore фильтрует записи базового ядра по своему уровню.
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
+
+func newLogger(level zapcore.LevelEnabler) *zap.SugaredLogger {
+	return zap.New(&levelCore{Core: baseCore, level: level}, zap.AddCaller()).Sugar()
+}
+
+// Component возвращает логгер компонента с его собственным уровнем.
+func Component(name string) *zap.SugaredLogger {
+	return newLogger(componentLevel(name))
+}
+
+// ForComponent переключает логгер на уровень компонента, сохраняя его поля
+// (request_id и т.п.). Логгеры, созданные не этим пакетом, возвращаются как есть.
+func ForComponent(l *zap.SugaredLogger, name string) *zap.SugaredLogger {
+	return l.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			return &levelCore{Core: lc.Core, level: componentLevel(name)}
+		}
+		return core
+	}))
+}
+
+// Levels возвращает глобальный уровень и уровни компонентов, заданные отдельно.
+func Levels() (string, map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	components := make(map[string]string)
+	for _, component := range Components {
+		if o := overrides[component]; o.set.Load() {
+			components[component] = o.level.Level().String()
+		}
+	}
+	return globalLevel.Level().String(), components
+}
+
+// SetLevel меняет уровень во время работы. Пустой component — глобальный
+// уровень; пустой level у компонента снимает его собственный уровень.
+// При revertAfter > 0 прежний уровень вернётся через указанное время.
+func SetLevel(component, level string, revertAfter time.Duration) error {
+	var lvl zapcore.Level
+	if level != "" || component == "" {
+		var err error
+		if lvl, err = ParseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if component != "" {
+		if _, ok := overrides[component]; !ok {
+			return fmt.Errorf("unknown log component %q", component)
+		}
+	}
+
+	restore := snapshot(component)
+	if timer := reverts[component]; timer != nil {
+		timer.Stop()
+		delete(reverts, component)
+	}
+
+	apply(component, level, lvl)
+
+	if revertAfter > 0 {
+		reverts[component] = time.AfterFunc(revertAfter, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			delete(reverts, component)
+			restore()
+		})
+	}
+	return nil
+}
+
+// resetLevels выставляет глобальный уровень и снимает все переопределения.
+func resetLevels(lvl zapcore.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for component, timer := range reverts {
+		timer.Stop()
+		delete(reverts, component)
+	}
+	for _, o := range overrides {
+		o.set.Store(false)
+	}
+	globalLevel.SetLevel(lvl)
+}
+
+// ResetLevels возвращает уровни к настроенному level, например по SIGHUP.
+func ResetLevels(level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	resetLevels(lvl)
+	return nil
+}
+
+func apply(component, level string, lvl zapcore.Level) {
+	if component == "" {
+		globalLevel.SetLevel(lvl)
+		return
+	}
+	overrides[component].level.SetLevel(lvl)
+	overrides[component].set.Store(level != "")
+}
+
+// snapshot запоминает текущий уровень, чтобы вернуть его по таймеру. Вызывается под mu.
+func snapshot(component string) func() {
+	if component == "" {
+		lvl := globalLevel.Level()
+		return func() { globalLevel.SetLevel(lvl) }
+	}
+	o := overrides[component]
+	lvl, was := o.level.Level(), o.set.Load()
+	return func() {
+		o.level.SetLevel(lvl)
+		o.set.Store(was)
+	}
+}
@@ -1,48 +1,82 @@
 package logger
 
 import (
+	"fmt"
 	"log"
 	"os"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var SugaredLogger *zap.SugaredLogger
 
-func InitLogger(level string) {
-	var atomicLevel zap.AtomicLevel
+// baseCore пишет всё начиная с debug; уровень проверяет обёртка levelCore,
+// поэтому у глобального логгера и у компонентов уровни независимы.
+var baseCore zapcore.Core = zapcore.NewNopCore()
 
-	switch level {
-	case "info":
-		atomicLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
-	case "warn":
-		atomicLevel = zap.NewAtomicLevelAt(zap.WarnLevel)
-	case "error":
-		atomicLevel = zap.NewAtomicLevelAt(zap.ErrorLevel)
-	default:
-		atomicLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
-	}
-
-	err := os.MkdirAll("/app/logger", 0755)
-	if err != nil {
-		log.Fatalf("Не удалось создать директорию логов: %v", err)
-	}
+type Options struct {
+	Level string
+	// Format — json (по умолчанию) или console.
+	Format string
+	// File — дополнительный файл логов с ротацией по размеру. Пусто — только stdout.
+	File       string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+}
 
-	file, err := os.OpenFile("logger/info.log", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+func InitLogger(opts Options) {
+	lvl, err := ParseLevel(opts.Level)
 	if err != nil {
-		log.Fatalf("Не удалось открыть файл логов: %v", err)
+		log.Printf("%v, используется info", err)
 	}
+	resetLevels(lvl)
 
 	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	encoderConfig.TimeKey = "time"
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
-	consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
+	var encoder zapcore.Encoder
+	if opts.Format == "console" {
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	}
 
-	fileWriter := zapcore.AddSync(file)
-	core := zapcore.NewCore(consoleEncoder, fileWriter, atomicLevel)
+	cores := []zapcore.Core{
+		zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), zap.DebugLevel),
+	}
+	if opts.File != "" {
+		// Файл пишется в JSON независимо от Format: его читают сборщики логов.
+		file := &lumberjack.Logger{
+			Filename:   opts.File,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAgeDays,
+			Compress:   true,
+		}
+		cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(file), zap.DebugLevel))
+	}
+
+	baseCore = zapcore.NewTee(cores...)
+	SugaredLogger = newLogger(globalLevel)
+}
 
-	logger := zap.New(core, zap.AddCaller())
-	SugaredLogger = logger.Sugar()
+// ParseLevel разбирает уровень логирования: debug, info, warn или error.
+func ParseLevel(s string) (zapcore.Level, error) {
+	switch s {
+	case "debug":
+		return zap.DebugLevel, nil
+	case "info", "":
+		return zap.InfoLevel, nil
+	case "warn":
+		return zap.WarnLevel, nil
+	case "error":
+		return zap.ErrorLevel, nil
+	default:
+		return zap.InfoLevel, fmt.Errorf("unknown log level %q", s)
+	}
 }
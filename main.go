@@ -1,61 +1,116 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"subscribers/config"
 	"subscribers/internal/db"
-	"subscribers/internal/handlers"
 	"subscribers/logger"
 
 	_ "subscribers/docs"
 
-	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
 )
 
 // @title Subscriptions API
 // @version 1.0
 // @description API для управления подписками
 // @host localhost:8080
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Bearer <ADMIN_TOKEN>
 func main() {
-	cfg := config.LoadConfig()
+	cfg := &config.Config{}
+	var configFile string
 
-	logger.InitLogger(cfg.LogLevel)
-	defer logger.SugaredLogger.Sync()
+	root := &cobra.Command{
+		Use:           "subscribers",
+		Short:         "Сервис управления подписками",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			loaded, err := config.Load(config.LoadOptions{
+				File:   configFile,
+				DotEnv: ".env",
+				Flags:  config.FlagValues(cmd.Flags()),
+			})
+			if loaded != nil && cmd.Annotations[skipValidation] == "" {
+				err = errors.Join(err, loaded.Validate())
+			}
+			if err != nil {
+				return fmt.Errorf("invalid configuration:\n%w", err)
+			}
+			*cfg = *loaded
 
-	logger.SugaredLogger.Info("The logger is initialized")
-
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
-
-	gormDB := db.ConnectGORM(dsn)
-	if gormDB == nil {
-		logger.SugaredLogger.Fatal("Couldn't connect to the database, shutting down")
+			logger.InitLogger(logger.Options{
+				Level:      cfg.LogLevel,
+				Format:     cfg.LogFormat,
+				File:       cfg.LogFile,
+				MaxSizeMB:  cfg.LogMaxSizeMB,
+				MaxBackups: cfg.LogMaxBackups,
+				MaxAgeDays: cfg.LogMaxAgeDays,
+			})
+			logger.SugaredLogger.Info("The logger is initialized")
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			logger.SugaredLogger.Sync()
+		},
+		// Без подкоманды бинарник ведёт себя как раньше и запускает сервер.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(cfg)
+		},
 	}
 
-	if err := db.AutoMigrate(gormDB); err != nil {
-		logger.SugaredLogger.Errorf("Migration error: %v", err)
-	}
+	// Флаги перекрывают файл конфигурации и переменные окружения.
+	root.PersistentFlags().StringVarP(&configFile, "config", "c", "", "YAML файл конфигурации (по умолчанию CONFIG_FILE)")
+	config.BindFlags(root.PersistentFlags())
 
-	router := gin.Default()
+	root.AddCommand(
+		serveCommand(cfg),
+		migrateCommand(cfg),
+		seedCommand(cfg),
+		recalcCommand(cfg),
+		purgeCommand(cfg),
+		catalogCommand(cfg),
+		checkConfigCommand(cfg),
+		printConfigCommand(cfg),
+	)
 
-	router.Static("/docs", "./docs")
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if err := root.Execute(); err != nil {
+		// PersistentPostRun после ошибки не вызывается.
+		if logger.SugaredLogger != nil {
+			logger.SugaredLogger.Sync()
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
 
-	router.POST("/createSubscription", handlers.CreateSubscriptionHandler(gormDB))
-	router.GET("/subscriptions", handlers.GetSubscriptionsHandler(gormDB))
-	router.GET("/subscriptions/:id", handlers.GetSubscriptionHandler(gormDB))
-	router.PATCH("/subscriptions/:id", handlers.UpdateSubscriptionHandler(gormDB))
-	router.DELETE("/subscriptions/:id", handlers.DeleteSubscriptionHandler(gormDB))
-	router.GET("/subscriptions/total", handlers.GetSubscriptionsTotalHandler(gormDB))
-	router.GET("/", func(ctx *gin.Context) {
-		ctx.Redirect(302, "/swagger/index.html")
-	})
+// skipValidation помечает команды, которые должны работать и с невалидной
+// конфигурацией: они сами сообщают о её проблемах.
+const skipValidation = "skip-validation"
 
-	logger.SugaredLogger.Infof("The server is running and listening on the port %s", cfg.AppPort)
+func connectDB(ctx context.Context, cfg *config.Config) *gorm.DB {
+	gormDB := db.ConnectGORM(ctx, dbOptions(cfg))
+	if gormDB == nil {
+		logger.SugaredLogger.Fatal("Couldn't connect to the database, shutting down")
+	}
+	return gormDB
+}
 
-	if err := router.Run(":" + cfg.AppPort); err != nil {
-		logger.SugaredLogger.Fatalf("Failed to start server: %v", err)
+func dbOptions(cfg *config.Config) db.Options {
+	return db.Options{
+		DSN:             cfg.DSN(),
+		ReplicaDSNs:     cfg.ReplicaDSNs(),
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+		MaxWait:         cfg.DBConnectTimeout,
 	}
 }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"subscribers/config"
+	"subscribers/internal/db"
+	"subscribers/internal/models"
+	"subscribers/internal/outbox"
+	"subscribers/internal/services"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+func recalcCommand(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "recalc",
+		Short: "Пересчитать сохранённые агрегаты",
+		Long: `Пересчитывает данные, производные от подписок. Итоги считаются на лету при
+каждом запросе, а в базе хранятся только находки анализа подписок: команда
+запускает анализ сразу, не дожидаясь INSIGHTS_INTERVAL. Уже сохранённые и
+скрытые находки не создаются заново.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gormDB := connectDB(cmd.Context(), cfg)
+			created, err := services.AnalyzeSubscriptions(gormDB, time.Now().UTC())
+			if err != nil {
+				return fmt.Errorf("failed to analyze subscriptions: %w", err)
+			}
+			fmt.Printf("recalculated: %d new insights\n", created)
+			return nil
+		},
+	}
+}
+
+func purgeCommand(cfg *config.Config) *cobra.Command {
+	var olderThan time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Окончательно удалить удалённые подписки и обработанные события",
+		Long: `Удаляет из базы:
+  - подписки, удалённые через API (soft delete);
+  - события outbox, уже опубликованные в брокер;
+  - успешно доставленные webhook'и.
+С --older-than удаляются только записи старше указанного срока.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gormDB := connectDB(cmd.Context(), cfg)
+			cutoff := time.Now().UTC().Add(-olderThan)
+
+			// Всё удаляется в одной транзакции: прерванный запуск не оставит
+			// подписки без участников или находки без подписок.
+			var subs, events, deliveries int64
+			err := gormDB.Transaction(func(tx *gorm.DB) error {
+				purged := tx.Unscoped().Model(&models.Subscription{}).Select("id").
+					Where("deleted_at IS NOT NULL AND deleted_at <= ?", cutoff)
+				if err := tx.Where("subscription_id IN (?)", purged).
+					Delete(&models.SubscriptionMember{}).Error; err != nil {
+					return fmt.Errorf("failed to purge subscription members: %w", err)
+				}
+				if err := tx.Where("subscription_id IN (?)", purged).
+					Delete(&models.SubscriptionTag{}).Error; err != nil {
+					return fmt.Errorf("failed to purge subscription tags: %w", err)
+				}
+				if err := tx.Where("subscription_id IN (?)", purged).
+					Delete(&models.PriceChange{}).Error; err != nil {
+					return fmt.Errorf("failed to purge price changes: %w", err)
+				}
+				if err := tx.Where("subscription_id IN (?) OR related_id IN (?)", purged, purged).
+					Delete(&models.Insight{}).Error; err != nil {
+					return fmt.Errorf("failed to purge insights: %w", err)
+				}
+
+				result := tx.Unscoped().
+					Where("deleted_at IS NOT NULL AND deleted_at <= ?", cutoff).
+					Delete(&models.Subscription{})
+				if result.Error != nil {
+					return fmt.Errorf("failed to purge subscriptions: %w", result.Error)
+				}
+				subs = result.RowsAffected
+
+				var err error
+				events, err = outbox.Prune(tx, cutoff, false)
+				if err != nil {
+					return fmt.Errorf("failed to purge outbox events: %w", err)
+				}
+
+				result = tx.
+					Where("status = ? AND delivered_at <= ?", models.DeliveryStatusDelivered, cutoff).
+					Delete(&models.WebhookDelivery{})
+				if result.Error != nil {
+					return fmt.Errorf("failed to purge webhook deliveries: %w", result.Error)
+				}
+				deliveries = result.RowsAffected
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("purged: %d subscriptions, %d outbox events, %d webhook deliveries\n",
+				subs, events, deliveries)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "удалять только записи старше срока, например 720h")
+	return cmd
+}
+
+func checkConfigCommand(cfg *config.Config) *cobra.Command {
+	var connect bool
+
+	cmd := &cobra.Command{
+		Use:         "check-config",
+		Short:       "Проверить конфигурацию",
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{skipValidation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfg.Validate(); err != nil {
+				return fmt.Errorf("invalid configuration:\n%w", err)
+			}
+			if connect {
+				opts := dbOptions(cfg)
+				opts.MaxWait = 0
+				if gormDB := db.ConnectGORM(cmd.Context(), opts); gormDB == nil {
+					return fmt.Errorf("couldn't connect to the database at %s:%s", cfg.DBHost, cfg.DBPort)
+				}
+			}
+			fmt.Println("configuration is valid")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&connect, "connect", false, "дополнительно проверить подключение к базе данных")
+	return cmd
+}
+
+func printConfigCommand(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "print-config",
+		Short: "Показать итоговую конфигурацию (секреты скрыты)",
+		Long: `Печатает конфигурацию в формате YAML файла после объединения всех источников:
+значения по умолчанию < файл (--config) < .env < переменные окружения < флаги.
+В комментарии к каждому значению указан его источник.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{skipValidation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cfg.WriteYAML(cmd.OutOrStdout())
+		},
+	}
+}
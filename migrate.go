@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"subscribers/config"
+	"subscribers/internal/db"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func migrateCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Управление схемой базы данных",
+	}
+
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Создать или обновить таблицы",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := db.AutoMigrate(connectDB(cmd.Context(), cfg)); err != nil {
+				return fmt.Errorf("migration error: %w", err)
+			}
+			fmt.Println("migrations applied")
+			return nil
+		},
+	}
+
+	var confirmed bool
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "Удалить все таблицы сервиса вместе с данными",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !confirmed {
+				return errors.New("this drops all tables and data, pass --yes to confirm")
+			}
+			if err := db.MigrateDown(connectDB(cmd.Context(), cfg)); err != nil {
+				return fmt.Errorf("migration error: %w", err)
+			}
+			fmt.Println("all tables dropped")
+			return nil
+		},
+	}
+	down.Flags().BoolVar(&confirmed, "yes", false, "подтвердить удаление")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Показать, какие таблицы и колонки отсутствуют",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statuses, err := db.MigrationStatus(connectDB(cmd.Context(), cfg))
+			if err != nil {
+				return err
+			}
+
+			pending := false
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TABLE\tSTATUS")
+			for _, s := range statuses {
+				state := "applied"
+				switch {
+				case !s.Exists:
+					state = "missing"
+					pending = true
+				case len(s.MissingColumns) > 0:
+					state = "missing columns: " + strings.Join(s.MissingColumns, ", ")
+					pending = true
+				}
+				fmt.Fprintf(w, "%s\t%s\n", s.Table, state)
+			}
+			w.Flush()
+
+			if pending {
+				return errors.New("there are pending migrations, run `migrate up`")
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(up, down, status)
+	return cmd
+}
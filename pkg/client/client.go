@@ -0,0 +1,166 @@
+// Package client — типизированный Go-клиент HTTP API сервиса подписок.
+//
+//	c := client.New("http://localhost:8080")
+//	id, err := c.CreateSubscription(ctx, client.CreateSubscriptionRequest{...})
+//	if errors.Is(err, client.ErrConflict) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	headers    http.Header
+	maxRetries int
+	backoff    time.Duration
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries задаёт число повторов при ответах 5xx и сетевых ошибках
+// и начальную задержку между ними (удваивается с каждой попыткой).
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithHeader добавляет заголовок ко всем запросам.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Set(key, value)
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		headers:    make(http.Header),
+		maxRetries: 3,
+		backoff:    200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// do выполняет запрос и декодирует JSON ответа в out (если out != nil).
+// Повторяются только идемпотентные методы: повтор POST может создать дубликат.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	retries := 0
+	if method != http.MethodPost {
+		retries = c.maxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return err
+			}
+		}
+
+		retry, err := c.attempt(ctx, method, endpoint, payload, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+func (c *Client) attempt(ctx context.Context, method, endpoint string, payload []byte, out interface{}) (bool, error) {
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bodyReader)
+	if err != nil {
+		return false, err
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, err
+	}
+
+	if resp.StatusCode >= 400 {
+		apiErr := newAPIError(resp.StatusCode, data)
+		return resp.StatusCode >= 500, apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, fmt.Errorf("decode response: %w", err)
+	}
+	return false, nil
+}
+
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	delay := c.backoff << (attempt - 1)
+	if delay > 0 {
+		delay += time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
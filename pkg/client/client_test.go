@@ -0,0 +1,282 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	database "subscribers/internal/db"
+	"subscribers/internal/health"
+	"subscribers/internal/router"
+	"subscribers/logger"
+	"subscribers/pkg/client"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	glog "gorm.io/gorm/logger"
+)
+
+// Тесты поднимают настоящий роутер поверх тестовой базы PostgreSQL из
+// TEST_DATABASE_DSN, например:
+//
+//	TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=subscribers_test sslmode=disable" go test ./pkg/client/
+//
+// Без неё тесты пропускаются, но в CI (переменная CI) её отсутствие — ошибка:
+// там база поднимается workflow'ом. Каждый тест работает со своим
+// пользователем, поэтому базу можно не очищать.
+
+var testDB *gorm.DB
+
+func TestMain(m *testing.M) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" && os.Getenv("CI") != "" {
+		fmt.Fprintln(os.Stderr, "TEST_DATABASE_DSN is required in CI")
+		os.Exit(1)
+	}
+	if dsn != "" {
+		logger.InitLogger(logger.Options{Level: "error"})
+		gin.SetMode(gin.TestMode)
+
+		var err error
+		testDB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: glog.Default.LogMode(glog.Silent)})
+		if err != nil {
+			logger.SugaredLogger.Fatalf("Failed to connect to the test database: %v", err)
+		}
+		if err := database.AutoMigrate(testDB); err != nil {
+			logger.SugaredLogger.Fatalf("Failed to migrate the test database: %v", err)
+		}
+	}
+	os.Exit(m.Run())
+}
+
+// newServer запускает роутер сервиса. wrap, если задан, оборачивает его
+// обработчик, например чтобы подменить часть ответов.
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) *client.Client {
+	t.Helper()
+	if testDB == nil {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	engine, err := router.New(router.Dependencies{
+		DB:      testDB,
+		Health:  health.NewChecker(time.Second),
+		Service: "subscribers-test",
+	})
+	if err != nil {
+		t.Fatalf("router.New: %v", err)
+	}
+	var handler http.Handler = engine
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return client.New(server.URL, client.WithRetries(2, time.Millisecond))
+}
+
+func createSubscription(t *testing.T, c *client.Client, userID uuid.UUID, service string, price int, start string) uuid.UUID {
+	t.Helper()
+	id, err := c.CreateSubscription(context.Background(), client.CreateSubscriptionRequest{
+		ServiceName: service,
+		Price:       price,
+		UserID:      userID.String(),
+		StartDate:   start,
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription(%s): %v", service, err)
+	}
+	return id
+}
+
+func TestSubscriptionLifecycle(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	id := createSubscription(t, c, userID, "Test Lifecycle", 500, "01-2024")
+
+	sub, err := c.GetSubscription(ctx, id)
+	if err != nil {
+		t.Fatalf("GetSubscription: %v", err)
+	}
+	if sub.ServiceName != "Test Lifecycle" || sub.MonthlyPrice != 500 || sub.UserID != userID {
+		t.Fatalf("GetSubscription = %+v", sub)
+	}
+	if sub.StartedAt != (client.YearMonth{Year: 2024, Month: time.January}) {
+		t.Fatalf("StartedAt = %v, want 2024-01", sub.StartedAt)
+	}
+
+	price, endDate := 700, "06-2024"
+	err = c.UpdateSubscription(ctx, id, client.UpdateSubscriptionRequest{Price: &price, EndDate: &endDate})
+	if err != nil {
+		t.Fatalf("UpdateSubscription: %v", err)
+	}
+	sub, err = c.GetSubscription(ctx, id)
+	if err != nil {
+		t.Fatalf("GetSubscription after update: %v", err)
+	}
+	if sub.MonthlyPrice != 700 || sub.EndedAt == nil || *sub.EndedAt != (client.YearMonth{Year: 2024, Month: time.June}) {
+		t.Fatalf("after update: price %d, ended_at %v", sub.MonthlyPrice, sub.EndedAt)
+	}
+
+	if err := c.DeleteSubscription(ctx, id); err != nil {
+		t.Fatalf("DeleteSubscription: %v", err)
+	}
+	if _, err := c.GetSubscription(ctx, id); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetSubscription after delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListSubscriptionsPagination(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	var want []uuid.UUID
+	for i, service := range []string{"Test A", "Test B", "Test C", "Test D", "Test E"} {
+		start := time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC).Format("01-2006")
+		want = append(want, createSubscription(t, c, userID, service, 100, start))
+	}
+
+	page, err := c.ListSubscriptions(ctx, userID, client.ListOptions{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("ListSubscriptions: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != want[2] || page[1].ID != want[3] {
+		t.Fatalf("page with limit 2, offset 2 = %v, want %v", ids(page), want[2:4])
+	}
+
+	var got []uuid.UUID
+	for sub, err := range c.AllSubscriptions(ctx, userID, 2) {
+		if err != nil {
+			t.Fatalf("AllSubscriptions: %v", err)
+		}
+		got = append(got, sub.ID)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AllSubscriptions returned %d subscriptions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("AllSubscriptions[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSubscriptionsTotal(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	createSubscription(t, c, userID, "Test Music", 300, "01-2024")
+	createSubscription(t, c, userID, "Test Video", 800, "03-2024")
+
+	total, err := c.GetSubscriptionsTotal(ctx, userID, client.TotalOptions{})
+	if err != nil {
+		t.Fatalf("GetSubscriptionsTotal: %v", err)
+	}
+	if total != 1100 {
+		t.Fatalf("total = %d, want 1100", total)
+	}
+
+	total, err = c.GetSubscriptionsTotal(ctx, userID, client.TotalOptions{ServiceName: "Test Video"})
+	if err != nil {
+		t.Fatalf("GetSubscriptionsTotal by service: %v", err)
+	}
+	if total != 800 {
+		t.Fatalf("total for Test Video = %d, want 800", total)
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	createSubscription(t, c, userID, "Test Conflict", 100, "01-2024")
+	_, err := c.CreateSubscription(ctx, client.CreateSubscriptionRequest{
+		ServiceName: "Test Conflict",
+		Price:       100,
+		UserID:      userID.String(),
+		StartDate:   "02-2024",
+	})
+	if !errors.Is(err, client.ErrConflict) {
+		t.Fatalf("duplicate CreateSubscription: err = %v, want ErrConflict", err)
+	}
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict || apiErr.Message == "" {
+		t.Fatalf("duplicate CreateSubscription: err = %#v, want APIError 409 with a message", err)
+	}
+
+	if _, err := c.GetSubscription(ctx, uuid.New()); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetSubscription of an unknown ID: err = %v, want ErrNotFound", err)
+	}
+	err = c.DeleteSubscription(ctx, uuid.New())
+	if !errors.Is(err, client.ErrNotFound) || errors.Is(err, client.ErrConflict) {
+		t.Fatalf("DeleteSubscription of an unknown ID: err = %v, want only ErrNotFound", err)
+	}
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	var failures, requests atomic.Int32
+	failures.Store(2)
+	c := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if failures.Add(-1) >= 0 {
+				http.Error(w, `{"error": "temporarily unavailable"}`, http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// Два ответа 503 укладываются в два повтора.
+	subs, err := c.ListSubscriptions(ctx, userID, client.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListSubscriptions after two 503: %v", err)
+	}
+	if len(subs) != 0 || requests.Load() != 3 {
+		t.Fatalf("got %d subscriptions in %d requests, want 0 in 3", len(subs), requests.Load())
+	}
+
+	// Три ответа 503 — больше, чем повторов.
+	failures.Store(3)
+	requests.Store(0)
+	_, err = c.ListSubscriptions(ctx, userID, client.ListOptions{})
+	if !errors.Is(err, client.ErrServer) || requests.Load() != 3 {
+		t.Fatalf("err = %v after %d requests, want ErrServer after 3", err, requests.Load())
+	}
+
+	// POST не повторяется: повтор мог бы создать дубликат.
+	failures.Store(1)
+	requests.Store(0)
+	_, err = c.CreateSubscription(ctx, client.CreateSubscriptionRequest{
+		ServiceName: "Test Retry",
+		Price:       100,
+		UserID:      userID.String(),
+		StartDate:   "01-2024",
+	})
+	if !errors.Is(err, client.ErrServer) || requests.Load() != 1 {
+		t.Fatalf("CreateSubscription: err = %v after %d requests, want ErrServer after 1", err, requests.Load())
+	}
+}
+
+func ids(subs []client.Subscription) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(subs))
+	for _, sub := range subs {
+		result = append(result, sub.ID)
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Ошибки, соответствующие кодам ответа сервера. Проверяются через errors.Is.
+var (
+	ErrBadRequest  = errors.New("bad request")
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrRateLimited = errors.New("rate limited")
+	ErrServer      = errors.New("server error")
+)
+
+// APIError — ответ сервера с кодом 4xx/5xx. Message берётся из поля "error".
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("subscribers API: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+func newAPIError(status int, body []byte) *APIError {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	message := string(body)
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		message = payload.Error
+	}
+	return &APIError{StatusCode: status, Message: message}
+}
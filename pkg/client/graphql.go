@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+type GraphQLError struct {
+	Message string `json:"message"`
+}
+
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// GraphQL выполняет запрос к /graphql и декодирует поле data в out.
+// Ошибки выполнения возвращаются как GraphQLErrors.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	req := map[string]interface{}{"query": query}
+	if variables != nil {
+		req["variables"] = variables
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/graphql", nil, req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return resp.Errors
+	}
+	if out == nil {
+		return nil
+	}
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return errors.New("graphql: empty response")
+	}
+	return json.Unmarshal(resp.Data, out)
+}
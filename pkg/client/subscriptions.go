@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateSubscription(ctx context.Context, req CreateSubscriptionRequest) (uuid.UUID, error) {
+	var resp struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/createSubscription", nil, req, &resp); err != nil {
+		return uuid.Nil, err
+	}
+	return resp.ID, nil
+}
+
+func (c *Client) GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	var sub Subscription
+	if err := c.do(ctx, http.MethodGet, "/subscriptions/"+id.String(), nil, nil, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+type ListOptions struct {
+	Limit  int
+	Offset int
+}
+
+// ListSubscriptions возвращает одну страницу подписок пользователя.
+// При нулевом Limit сервер отдаёт все подписки.
+func (c *Client) ListSubscriptions(ctx context.Context, userID uuid.UUID, opts ListOptions) ([]Subscription, error) {
+	query := url.Values{"user_id": {userID.String()}}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+
+	var subs []Subscription
+	if err := c.do(ctx, http.MethodGet, "/subscriptions", query, nil, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// AllSubscriptions обходит подписки пользователя постранично:
+//
+//	for sub, err := range c.AllSubscriptions(ctx, userID, 100) { ... }
+//
+// После первой ошибки итерация прекращается.
+func (c *Client) AllSubscriptions(ctx context.Context, userID uuid.UUID, pageSize int) iter.Seq2[Subscription, error] {
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	return func(yield func(Subscription, error) bool) {
+		offset := 0
+		for {
+			page, err := c.ListSubscriptions(ctx, userID, ListOptions{Limit: pageSize, Offset: offset})
+			if err != nil {
+				yield(Subscription{}, err)
+				return
+			}
+			for _, sub := range page {
+				if !yield(sub, nil) {
+					return
+				}
+			}
+			if len(page) < pageSize {
+				return
+			}
+			offset += len(page)
+		}
+	}
+}
+
+func (c *Client) UpdateSubscription(ctx context.Context, id uuid.UUID, req UpdateSubscriptionRequest) error {
+	return c.do(ctx, http.MethodPatch, "/subscriptions/"+id.String(), nil, req, nil)
+}
+
+func (c *Client) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/subscriptions/"+id.String(), nil, nil, nil)
+}
+
+type TotalOptions struct {
+	ServiceName string
+	// Даты в формате 01-2006 или 2006-01.
+	StartDate string
+	EndDate   string
+}
+
+func (c *Client) GetSubscriptionsTotal(ctx context.Context, userID uuid.UUID, opts TotalOptions) (int, error) {
+	query := url.Values{"user_id": {userID.String()}}
+	if opts.ServiceName != "" {
+		query.Set("service_name", opts.ServiceName)
+	}
+	if opts.StartDate != "" {
+		query.Set("start_date", opts.StartDate)
+	}
+	if opts.EndDate != "" {
+		query.Set("end_date", opts.EndDate)
+	}
+
+	var resp struct {
+		TotalPrice int `json:"total_price"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/subscriptions/total", query, nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.TotalPrice, nil
+}
@@ -0,0 +1,14 @@
+package client
+
+import "subscribers/internal/models"
+
+// Типы запросов и ответов совпадают с серверными моделями.
+type (
+	Subscription              = models.Subscription
+	YearMonth                 = models.YearMonth
+	CreateSubscriptionRequest = models.CreateSubscriptionRequest
+	UpdateSubscriptionRequest = models.UpdateSubscriptionRequest
+	CreateWebhookRequest      = models.CreateWebhookRequest
+	WebhookEndpoint           = models.WebhookEndpoint
+	WebhookDelivery           = models.WebhookDelivery
+)
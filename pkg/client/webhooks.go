@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (*WebhookEndpoint, error) {
+	var endpoint WebhookEndpoint
+	if err := c.do(ctx, http.MethodPost, "/webhooks", nil, req, &endpoint); err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]WebhookEndpoint, error) {
+	var endpoints []WebhookEndpoint
+	if err := c.do(ctx, http.MethodGet, "/webhooks", nil, nil, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/webhooks/"+id.String(), nil, nil, nil)
+}
+
+func (c *Client) ListFailedWebhookDeliveries(ctx context.Context) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	if err := c.do(ctx, http.MethodGet, "/webhooks/deliveries/failed", nil, nil, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (c *Client) RedeliverWebhook(ctx context.Context, deliveryID uuid.UUID) error {
+	return c.do(ctx, http.MethodPost, "/webhooks/deliveries/"+deliveryID.String()+"/redeliver", nil, nil, nil)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"subscribers/config"
+	"subscribers/internal/models"
+	"subscribers/internal/services"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var seedServices = []struct {
+	name     string
+	category string
+	minPrice int
+	maxPrice int
+}{
+	{"Netflix", models.CategoryEntertainment, 599, 1199},
+	{"Spotify", models.CategoryEntertainment, 199, 349},
+	{"YouTube Premium", models.CategoryEntertainment, 299, 449},
+	{"Яндекс Плюс", models.CategoryEntertainment, 299, 399},
+	{"Kinopoisk", models.CategoryEntertainment, 269, 399},
+	{"Apple Music", models.CategoryEntertainment, 169, 299},
+	{"iCloud+", models.CategoryUtilities, 59, 599},
+	{"Google One", models.CategoryUtilities, 139, 699},
+	{"ChatGPT Plus", models.CategorySoftware, 1900, 2100},
+	{"GitHub Copilot", models.CategorySoftware, 850, 1000},
+	{"JetBrains All Products", models.CategorySoftware, 2400, 2900},
+	{"Adobe Creative Cloud", models.CategorySoftware, 3500, 4200},
+	{"Duolingo", models.CategoryEducation, 399, 599},
+	{"Telegram Premium", models.CategoryUtilities, 299, 299},
+	{"Okko", models.CategoryEntertainment, 399, 599},
+}
+
+func seedCommand(cfg *config.Config) *cobra.Command {
+	var users, maxPerUser int
+	var seed int64
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Сгенерировать тестовые подписки",
+		Long: `Создаёт подписки для случайных пользователей: популярные сервисы, реалистичные цены,
+даты начала за последние три года, часть подписок уже завершена.
+Названия сводятся к каталогу, как при создании через API. Подписки пишутся
+напрямую в таблицу, события и webhook'и не создаются.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if users <= 0 || maxPerUser <= 0 {
+				return fmt.Errorf("--users and --max-per-user must be positive")
+			}
+			if maxPerUser > len(seedServices) {
+				maxPerUser = len(seedServices)
+			}
+			if seed == 0 {
+				seed = time.Now().UnixNano()
+			}
+			rnd := rand.New(rand.NewSource(seed))
+
+			gormDB := connectDB(cmd.Context(), cfg)
+			subs := generateSubscriptions(rnd, users, maxPerUser, time.Now().UTC())
+			if err := resolveSeedServices(gormDB, subs); err != nil {
+				return err
+			}
+			if err := gormDB.CreateInBatches(subs, 500).Error; err != nil {
+				return fmt.Errorf("failed to seed subscriptions: %w", err)
+			}
+
+			fmt.Printf("created %d subscriptions for %d users (seed %d)\n", len(subs), users, seed)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&users, "users", "n", 10, "количество пользователей")
+	cmd.Flags().IntVar(&maxPerUser, "max-per-user", 6, "максимум подписок на пользователя")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "seed генератора для воспроизводимых данных")
+	return cmd
+}
+
+func generateSubscriptions(rnd *rand.Rand, users, maxPerUser int, now time.Time) []models.Subscription {
+	current := models.YearMonth{Year: now.Year(), Month: now.Month()}
+
+	var subs []models.Subscription
+	for i := 0; i < users; i++ {
+		userID := uuid.New()
+		count := 1 + rnd.Intn(maxPerUser)
+
+		for _, idx := range rnd.Perm(len(seedServices))[:count] {
+			service := seedServices[idx]
+			price := service.minPrice
+			if service.maxPrice > service.minPrice {
+				price += rnd.Intn(service.maxPrice - service.minPrice + 1)
+			}
+			// Цены в рублях обычно заканчиваются на 9.
+			price = price/10*10 + 9
+
+			started := current.AddMonths(-rnd.Intn(36))
+			sub := models.Subscription{
+				ID:           uuid.New(),
+				ServiceName:  service.name,
+				Category:     service.category,
+				MonthlyPrice: price,
+				UserID:       userID,
+				StartedAt:    started,
+			}
+
+			if rnd.Intn(4) == 0 {
+				ended := started.AddMonths(1 + rnd.Intn(12))
+				if ended.After(current) {
+					ended = current
+				}
+				sub.EndedAt = &ended
+			}
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// resolveSeedServices сводит названия подписок к каталогу так же, как
+// CreateSubscription: каноническое название и service_id.
+func resolveSeedServices(db *gorm.DB, subs []models.Subscription) error {
+	resolved := make(map[string]*models.CatalogService)
+	for i := range subs {
+		name := subs[i].ServiceName
+		service, ok := resolved[name]
+		if !ok {
+			var err error
+			service, err = services.ResolveServiceName(db, name)
+			if err != nil {
+				return fmt.Errorf("service catalog lookup error: %w", err)
+			}
+			resolved[name] = service
+		}
+		if service != nil {
+			subs[i].ServiceName, subs[i].ServiceID = service.Name, &service.ID
+		}
+	}
+	return nil
+}
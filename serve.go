@@ -0,0 +1,393 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"subscribers/config"
+	"subscribers/internal/certs"
+	"subscribers/internal/db"
+	"subscribers/internal/graphqlapi"
+	"subscribers/internal/grpcserver"
+	"subscribers/internal/health"
+	"subscribers/internal/metrics"
+	"subscribers/internal/middleware"
+	"subscribers/internal/outbox"
+	"subscribers/internal/ratelimit"
+	"subscribers/internal/router"
+	"subscribers/internal/scheduler"
+	"subscribers/internal/tracing"
+	"subscribers/internal/webhooks"
+	"subscribers/logger"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func serveCommand(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Запустить HTTP и gRPC серверы (команда по умолчанию)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(cfg)
+		},
+	}
+}
+
+// serve запускает серверы и работает до сигнала остановки. Ошибка
+// возвращается, если один из серверов упал: процесс завершится с ненулевым кодом.
+func serve(cfg *config.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.LogLevel != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		ServiceName: cfg.ServiceName,
+		Endpoint:    cfg.OTLPEndpoint,
+		SampleRatio: cfg.TraceSampleRatio,
+	})
+	if err != nil {
+		logger.SugaredLogger.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	gormDB := connectDB(ctx, cfg)
+	if err := gormDB.Use(tracing.GormPlugin{}); err != nil {
+		logger.SugaredLogger.Errorf("Tracing error: %v", err)
+	}
+
+	if err := db.AutoMigrate(gormDB); err != nil {
+		logger.SugaredLogger.Errorf("Migration error: %v", err)
+	}
+	if err := metrics.RegisterDB(gormDB); err != nil {
+		logger.SugaredLogger.Errorf("Metrics error: %v", err)
+	}
+
+	checker := health.NewChecker(cfg.HealthCheckTimeout)
+	checker.Add("database", health.Database(gormDB))
+	checker.Add("migrations", health.Migrations(gormDB))
+
+	// Фоновые воркеры живут в своём контексте: его отменяют только после того,
+	// как серверы перестали принимать запросы.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	dispatcher := webhooks.NewDispatcher(gormDB, webhooks.Options{
+		PollInterval: cfg.WebhookPollInterval,
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		Timeout:      cfg.WebhookTimeout,
+	})
+	checker.Add("webhook_dispatcher", health.Running(dispatcher.Running))
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		dispatcher.Run(workersCtx)
+	}()
+
+	priceChanges := scheduler.NewPriceChanges(gormDB, cfg.PriceChangeInterval)
+	checker.Add("price_change_scheduler", health.Running(priceChanges.Running))
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		priceChanges.Run(workersCtx)
+	}()
+
+	insights := scheduler.NewInsights(gormDB, cfg.InsightsInterval)
+	checker.Add("insights_analyzer", health.Running(insights.Running))
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		insights.Run(workersCtx)
+	}()
+
+	var publisher outbox.Publisher
+	if cfg.BrokerType != "" {
+		var err error
+		publisher, err = outbox.NewPublisher(outbox.PublisherConfig{
+			Type:  cfg.BrokerType,
+			URL:   cfg.BrokerURL,
+			Topic: cfg.BrokerTopic,
+		})
+		if err != nil {
+			logger.SugaredLogger.Fatalf("Failed to create broker publisher: %v", err)
+		}
+
+		relay := outbox.NewRelay(gormDB, publisher, cfg.OutboxPollInterval)
+		checker.Add("outbox_relay", health.Running(relay.Running))
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			relay.Run(workersCtx)
+		}()
+	} else {
+		logger.SugaredLogger.Warn("BROKER_TYPE is not set, outbox events will not be published")
+	}
+
+	pruner := outbox.NewPruner(gormDB, cfg.OutboxRetention, cfg.BrokerType == "")
+	checker.Add("outbox_pruner", health.Running(pruner.Running))
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		pruner.Run(workersCtx)
+	}()
+
+	graphqlExecutor, err := graphqlapi.NewExecutor(gormDB, graphqlapi.Limits{
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+		MaxDepth:      cfg.GraphQLMaxDepth,
+	})
+	if err != nil {
+		logger.SugaredLogger.Fatalf("Failed to build GraphQL schema: %v", err)
+	}
+
+	rateLimiter, closeRateLimiter, err := newRateLimiter(ctx, cfg)
+	if err != nil {
+		logger.SugaredLogger.Fatalf("Failed to set up rate limiter: %v", err)
+	}
+
+	// Формат уже проверен в Config.Validate.
+	identities, _ := certs.ParseIdentities(cfg.TLSClientIdentities)
+
+	httpRouter, err := router.New(router.Dependencies{
+		DB:              gormDB,
+		GraphQL:         graphqlExecutor,
+		Health:          checker,
+		Service:         cfg.ServiceName,
+		AdminToken:      cfg.AdminToken,
+		AdminIdentities: cfg.AdminIdentities,
+		UserIdentities:  cfg.UserIdentities,
+		TrustedProxies:  cfg.TrustedProxies,
+		Identities:      identities,
+		RateLimiter:     rateLimiter,
+		RateLimits:      rateLimits(cfg),
+		CORS: middleware.CORSOptions{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowedMethods:   cfg.CORSAllowedMethods,
+			AllowedHeaders:   cfg.CORSAllowedHeaders,
+			AllowCredentials: cfg.CORSAllowCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		},
+		HSTSMaxAge:   cfg.HSTSMaxAge,
+		MaxBodyBytes: int64(cfg.MaxBodyBytes),
+	})
+	if err != nil {
+		logger.SugaredLogger.Fatalf("Failed to set up HTTP router: %v", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              ":" + cfg.AppPort,
+		Handler:           httpRouter,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+
+	if cfg.TLSCertFile != "" {
+		reloader, err := certs.NewReloader(certs.Options{
+			CertFile:     cfg.TLSCertFile,
+			KeyFile:      cfg.TLSKeyFile,
+			ClientCAFile: cfg.TLSClientCAFile,
+			ClientAuth:   certs.ClientAuth(cfg.TLSClientAuth),
+		})
+		if err != nil {
+			logger.SugaredLogger.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		httpServer.TLSConfig = reloader.TLSConfig()
+		go reloader.Watch(ctx, cfg.TLSReloadInterval)
+	}
+
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		logger.SugaredLogger.Fatalf("Failed to listen on gRPC port: %v", err)
+	}
+	grpcServer := grpcserver.New(gormDB, grpcserver.Options{
+		TLS:            httpServer.TLSConfig,
+		Identities:     identities,
+		UserIdentities: cfg.UserIdentities,
+	})
+
+	go reloadLogLevelOnHUP(ctx, cfg)
+
+	serverErrors := make(chan error, 3)
+	go func() {
+		logger.SugaredLogger.Infof("The gRPC server is listening on the port %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			serverErrors <- fmt.Errorf("gRPC server: %w", err)
+		}
+	}()
+	go func() {
+		var err error
+		if httpServer.TLSConfig != nil {
+			logger.SugaredLogger.Infof("The server is running and listening on the port %s (TLS, client auth: %s)", cfg.AppPort, cfg.TLSClientAuth)
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			logger.SugaredLogger.Infof("The server is running and listening on the port %s", cfg.AppPort)
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- fmt.Errorf("HTTP server: %w", err)
+		}
+	}()
+
+	var serverErr error
+	var healthServer *http.Server
+	if cfg.HealthPort != "" {
+		healthServer = &http.Server{
+			Addr:              ":" + cfg.HealthPort,
+			Handler:           router.NewHealth(checker),
+			ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		}
+		go func() {
+			logger.SugaredLogger.Infof("Health checks are served on the port %s", cfg.HealthPort)
+			if err := healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErrors <- fmt.Errorf("health server: %w", err)
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		logger.SugaredLogger.Info("Shutdown signal received, draining connections")
+	case serverErr = <-serverErrors:
+		logger.SugaredLogger.Errorf("Server failed, shutting down: %v", serverErr)
+	}
+	stop()
+	checker.SetShuttingDown()
+
+	// Пока серверы ещё принимают запросы, /readyz уже отвечает 503: балансировщик
+	// успевает убрать экземпляр из ротации до закрытия соединений.
+	if serverErr == nil && cfg.ShutdownDelay > 0 {
+		logger.SugaredLogger.Infof("Waiting %s before closing connections", cfg.ShutdownDelay)
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		logger.SugaredLogger.Errorf("HTTP server shutdown: %v", err)
+	}
+	stopGRPC(shutdownCtx, grpcServer)
+	if healthServer != nil {
+		if err := healthServer.Shutdown(shutdownCtx); err != nil {
+			logger.SugaredLogger.Errorf("Health server shutdown: %v", err)
+		}
+	}
+
+	stopWorkers()
+	waitWithContext(shutdownCtx, &workers)
+
+	if publisher != nil {
+		if err := publisher.Close(); err != nil {
+			logger.SugaredLogger.Errorf("Failed to close broker publisher: %v", err)
+		}
+	}
+
+	if err := closeRateLimiter(); err != nil {
+		logger.SugaredLogger.Errorf("Failed to close rate limiter: %v", err)
+	}
+
+	if sqlDB, err := gormDB.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			logger.SugaredLogger.Errorf("Failed to close database pool: %v", err)
+		}
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.SugaredLogger.Errorf("Failed to flush traces: %v", err)
+	}
+
+	logger.SugaredLogger.Info("Server stopped")
+	return serverErr
+}
+
+// newRateLimiter создаёт хранилище лимитов. Redis нужен, когда экземпляров
+// сервиса несколько и лимит должен быть общим.
+func newRateLimiter(ctx context.Context, cfg *config.Config) (ratelimit.Store, func() error, error) {
+	if cfg.RateLimitBackend != "redis" {
+		return ratelimit.NewMemoryStore(), func() error { return nil }, nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	store, err := ratelimit.NewRedisStore(pingCtx, cfg.RateLimitRedisURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.SugaredLogger.Info("Rate limits are stored in Redis")
+	return store, store.Close, nil
+}
+
+// rateLimits разбирает лимиты групп; формат уже проверен в Config.Validate.
+func rateLimits(cfg *config.Config) router.RateLimits {
+	api, _ := ratelimit.ParseLimit(cfg.RateLimitAPI)
+	total, _ := ratelimit.ParseLimit(cfg.RateLimitTotal)
+	graphql, _ := ratelimit.ParseLimit(cfg.RateLimitGraphQL)
+	return router.RateLimits{API: api, Total: total, GraphQL: graphql}
+}
+
+// reloadLogLevelOnHUP по SIGHUP перечитывает конфигурацию, возвращает уровни
+// логирования к LOG_LEVEL и снимает изменения, сделанные через /admin/log-level.
+func reloadLogLevelOnHUP(ctx context.Context, cfg *config.Config) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			reloaded, err := cfg.Reload()
+			if err == nil {
+				err = logger.ResetLevels(reloaded.LogLevel)
+			}
+			if err != nil {
+				logger.SugaredLogger.Errorf("SIGHUP: failed to reload log level: %v", err)
+				continue
+			}
+			logger.SugaredLogger.Warnf("SIGHUP: log level reset to %s", reloaded.LogLevel)
+		}
+	}
+}
+
+// stopGRPC дожидается завершения активных RPC, но не дольше, чем позволяет ctx.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.SugaredLogger.Warn("gRPC graceful stop timed out, closing connections")
+		server.Stop()
+	}
+}
+
+func waitWithContext(ctx context.Context, wg *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.SugaredLogger.Warn("Background workers did not stop in time")
+	}
+}